@@ -9,6 +9,13 @@ const (
 	AccountConditionSigningKeysUpdated = "SigningKeysUpdated"
 	AccountConditionJWTSecretReady     = "JWTSecretReady"
 	AccountConditionJWTPushed          = "JWTPushed"
+
+	AccountConditionAuthorizationResolved = "AuthorizationResolved"
+
+	// AccountConditionInSync reports whether the JWTs held by the Operator's push targets matched the Account JWT when
+	// last verified. It is informational only and does not contribute to the Ready condition, since any drift is
+	// repaired by pushing the JWT again.
+	AccountConditionInSync = "InSync"
 )
 
 var accountConditionSet = apis.NewLivingConditionSet(
@@ -17,6 +24,7 @@ var accountConditionSet = apis.NewLivingConditionSet(
 	AccountConditionOperatorResolved,
 	AccountConditionIssuerResolved,
 	AccountConditionSigningKeysUpdated,
+	AccountConditionAuthorizationResolved,
 	AccountConditionJWTSecretReady,
 	AccountConditionJWTPushed,
 )
@@ -88,6 +96,26 @@ func (s *AccountStatus) MarkSigningKeysUpdateUnknown(reason, messageFormat strin
 	accountConditionSet.Manage(s).MarkUnknown(AccountConditionSigningKeysUpdated, reason, messageFormat, messageA...)
 }
 
+// MarkAuthorizationResolved records the resolved auth callout configuration, authorization should be nil when the
+// Account does not configure an auth callout.
+func (s *AccountStatus) MarkAuthorizationResolved(authorization *AccountAuthorizationStatus) {
+	s.Authorization = authorization
+
+	accountConditionSet.Manage(s).MarkTrue(AccountConditionAuthorizationResolved)
+}
+
+func (s *AccountStatus) MarkAuthorizationResolveFailed(reason, messageFormat string, messageA ...interface{}) {
+	s.Authorization = nil
+
+	accountConditionSet.Manage(s).MarkFalse(AccountConditionAuthorizationResolved, reason, messageFormat, messageA...)
+}
+
+func (s *AccountStatus) MarkAuthorizationResolveUnknown(reason, messageFormat string, messageA ...interface{}) {
+	s.Authorization = nil
+
+	accountConditionSet.Manage(s).MarkUnknown(AccountConditionAuthorizationResolved, reason, messageFormat, messageA...)
+}
+
 func (s *AccountStatus) MarkJWTSecretReady() {
 	accountConditionSet.Manage(s).MarkTrue(AccountConditionJWTSecretReady)
 }
@@ -132,3 +160,15 @@ func (s *AccountStatus) MarkJWTPushFailed(reason, messageFormat string, messageA
 func (s *AccountStatus) MarkJWTPushUnknown(reason, messageFormat string, messageA ...interface{}) {
 	accountConditionSet.Manage(s).MarkUnknown(AccountConditionJWTPushed, reason, messageFormat, messageA...)
 }
+
+func (s *AccountStatus) MarkInSync() {
+	accountConditionSet.Manage(s).MarkTrue(AccountConditionInSync)
+}
+
+func (s *AccountStatus) MarkInSyncFailed(reason, messageFormat string, messageA ...interface{}) {
+	accountConditionSet.Manage(s).MarkFalse(AccountConditionInSync, reason, messageFormat, messageA...)
+}
+
+func (s *AccountStatus) MarkInSyncUnknown(reason, messageFormat string, messageA ...interface{}) {
+	accountConditionSet.Manage(s).MarkUnknown(AccountConditionInSync, reason, messageFormat, messageA...)
+}
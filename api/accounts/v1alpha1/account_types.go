@@ -26,6 +26,7 @@ SOFTWARE.
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -60,10 +61,14 @@ type AccountSpec struct {
 	UsersSelector *metav1.LabelSelector `json:"usersSelector,omitempty"`
 
 	// JWTSecretName is the name of the Secret that will be created to hold the JWT signing key for this Account.
-	JWTSecretName string `json:"jwtSecretName"`
+	// Defaults to the name of the Account suffixed with "-jwt".
+	// +optional
+	JWTSecretName string `json:"jwtSecretName,omitempty"`
 
-	// SeedSecretName is the name of the Secret that will be created to hold the seed for this Account.
-	SeedSecretName string `json:"seedSecretName"`
+	// SeedSecretName is the name of the Secret that will be created to hold the seed for this Account. Defaults to
+	// the name of the Account suffixed with "-seed".
+	// +optional
+	SeedSecretName string `json:"seedSecretName,omitempty"`
 
 	// SigningKeysSelector is the label selector to restrict which SigningKeys can be used to sign JWTs for this
 	// Account. SigningKeys must be in the same namespace as the Account.
@@ -77,6 +82,32 @@ type AccountSpec struct {
 
 	// Limits is a JWT claim for the Account.
 	Limits *OperatorLimits `json:"limits,omitempty"`
+
+	// Mappings is a JWT claim for the Account, mapping each source subject to one or more weighted destination
+	// subjects. The weights of the destinations for a source subject must not add up to more than 100.
+	Mappings map[string][]WeightedMapping `json:"mappings,omitempty"`
+
+	// Authorization configures an auth callout service for the Account. The public keys of the referenced Users and
+	// Accounts are resolved from their status and included in the Account JWT.
+	Authorization *AccountAuthorization `json:"authorization,omitempty"`
+}
+
+// AccountAuthorization is the auth callout configuration for an Account.
+type AccountAuthorization struct {
+	// AuthUsers are the names of the Users, in the same namespace as the Account, which the auth callout service
+	// connects as. These Users bypass the auth callout.
+	// +kubebuilder:validation:MinItems=1
+	AuthUsers []string `json:"authUsers"`
+
+	// AllowedAccounts are the Accounts which the auth callout service may place Users into. If the namespace of a
+	// reference is not set it defaults to the namespace of the Account.
+	// +optional
+	AllowedAccounts []InferredObjectReference `json:"allowedAccounts,omitempty"`
+
+	// XKeySecretName is the name of the Secret that will be created to hold the curve keypair used to encrypt auth
+	// callout requests. Requests are not encrypted if this is not set.
+	// +optional
+	XKeySecretName string `json:"xKeySecretName,omitempty"`
 }
 
 type AccountImport struct {
@@ -86,6 +117,11 @@ type AccountImport struct {
 	Token   string           `json:"token"`
 	To      string           `json:"to"`
 	Type    ImportExportType `json:"type"`
+
+	// TokenSecretRef is a reference to a key in a Secret, in the namespace of the Account, containing the activation
+	// token for this import. This is used instead of Token when set.
+	// +optional
+	TokenSecretRef *v1.SecretKeySelector `json:"tokenSecretRef,omitempty"`
 }
 
 type AccountExport struct {
@@ -101,6 +137,22 @@ type AccountExport struct {
 	AccountTokenPosition uint                   `json:"accountTokenPosition"`
 }
 
+// WeightedMapping is a destination subject of an Account subject mapping.
+type WeightedMapping struct {
+	// Subject is the destination subject, this must not contain wildcards.
+	Subject string `json:"subject"`
+
+	// Weight is the percentage of messages sent to this destination, defaults to 100 if not set.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Weight uint8 `json:"weight,omitempty"`
+
+	// Cluster restricts this destination to only apply within the named cluster.
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+}
+
 type AccountServiceLatency struct {
 	Sampling int    `json:"sampling"`
 	Results  string `json:"results"`
@@ -111,6 +163,10 @@ type OperatorLimits struct {
 	Nats      NatsLimits      `json:"nats,omitempty"`
 	Account   AccountLimits   `json:"account,omitempty"`
 	JetStream JetStreamLimits `json:"jetStream,omitempty"`
+
+	// TieredLimits sets JetStream limits per replica tier, keyed by tier name such as "R1" or "R3". This is mutually
+	// exclusive with JetStream.
+	TieredLimits map[string]JetStreamLimits `json:"tieredLimits,omitempty"`
 }
 
 type NatsLimits struct {
@@ -146,6 +202,67 @@ type AccountStatus struct {
 	KeyPair     *KeyPair                   `json:"keyPair,omitempty"`
 	SigningKeys []SigningKeyEmbeddedStatus `json:"signingKeys,omitempty"`
 	OperatorRef *InferredObjectReference   `json:"operatorRef,omitempty"`
+
+	// Revocations lists the User public keys which have been revoked by this Account, either because the User was
+	// deleted or because its keypair was replaced. These are included in the Account JWT until they are no longer
+	// required.
+	Revocations []UserRevocation `json:"revocations,omitempty"`
+
+	// Authorization holds the public keys resolved from .spec.authorization, which are included in the Account JWT.
+	Authorization *AccountAuthorizationStatus `json:"authorization,omitempty"`
+
+	// PushTargets records the result of pushing the Account JWT to each of the Operator's push targets.
+	PushTargets []PushTargetStatus `json:"pushTargets,omitempty"`
+
+	// LastVerificationTime is the time at which the JWTs held by the push targets were last compared with the Account
+	// JWT, see the InSync condition for the result.
+	LastVerificationTime *metav1.Time `json:"lastVerificationTime,omitempty"`
+}
+
+// PushTargetStatus is the result of pushing the Account JWT to one of the Operator's push targets.
+type PushTargetStatus struct {
+	// Name is the name of the push target.
+	Name string `json:"name"`
+
+	// Pushed is true if the last push to this target succeeded.
+	Pushed bool `json:"pushed"`
+
+	// JWTID is the ID of the Account JWT which was last pushed successfully.
+	JWTID string `json:"jwtID,omitempty"`
+
+	// LastPushTime is the time at which the JWT with JWTID was first pushed successfully.
+	LastPushTime *metav1.Time `json:"lastPushTime,omitempty"`
+
+	// Message describes why the last push to this target failed.
+	Message string `json:"message,omitempty"`
+}
+
+// AccountAuthorizationStatus is the resolved auth callout configuration for an Account.
+type AccountAuthorizationStatus struct {
+	// AuthUsers are the public keys of the Users referenced by .spec.authorization.authUsers.
+	AuthUsers []string `json:"authUsers"`
+
+	// AllowedAccounts are the public keys of the Accounts referenced by .spec.authorization.allowedAccounts.
+	AllowedAccounts []string `json:"allowedAccounts,omitempty"`
+
+	// XKey is the public curve key used to encrypt auth callout requests.
+	XKey string `json:"xKey,omitempty"`
+}
+
+// UserRevocation records the revocation of a User's public key within an Account JWT.
+type UserRevocation struct {
+	// PublicKey is the public key of the revoked User.
+	PublicKey string `json:"publicKey"`
+
+	// RevokedAt is the time of the revocation, any JWTs for PublicKey issued before this time are rejected.
+	RevokedAt metav1.Time `json:"revokedAt"`
+
+	// ExpiresAt is the expiry of the last JWT issued for PublicKey. Once passed, the revocation is no longer required
+	// and will be pruned. A nil value means the JWT never expires and the revocation is kept indefinitely.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// UserRef is the User which owned PublicKey at the time of revocation.
+	UserRef InferredObjectReference `json:"userRef"`
 }
 
 type OperatorRef struct {
@@ -155,6 +272,7 @@ type OperatorRef struct {
 
 //+genclient
 //+kubebuilder:object:root=true
+//+kubebuilder:storageversion
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Public Key",type=string,JSONPath=`.status.keyPair.publicKey`
 //+kubebuilder:printcolumn:name="Operator",type=string,JSONPath=`.status.operatorRef.name`
@@ -28,7 +28,8 @@ type InferredObjectReference struct {
 }
 
 type TypedObjectReference struct {
-	APIVersion string    `json:"apiVersion"`
+	// +optional
+	APIVersion string    `json:"apiVersion,omitempty"`
 	Kind       string    `json:"kind"`
 	Name       string    `json:"name"`
 	Namespace  string    `json:"namespace,omitempty"`
@@ -45,8 +46,9 @@ type Identity struct {
 }
 
 type SigningKeyEmbeddedStatus struct {
-	Name    string  `json:"name"`
-	KeyPair KeyPair `json:"keyPair,omitempty"`
+	Name    string           `json:"name"`
+	KeyPair KeyPair          `json:"keyPair,omitempty"`
+	Scope   *SigningKeyScope `json:"scope,omitempty"`
 }
 
 // IssuerReference provides the means to look up a signing key for generating an Account or User.
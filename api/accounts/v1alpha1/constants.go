@@ -13,4 +13,20 @@ const (
 	ReasonInvalidJWTSecret         = "InvalidJWTSecret"
 	ReasonInvalidCredentialsSecret = "InvalidCredentialsSecret"
 	ReasonJWTPushError             = "JWTPushError"
+	ReasonNotAllowed               = "NotAllowed"
+	ReasonInvalidLabelSelector     = "InvalidLabelSelector"
+	ReasonUnsupportedScope         = "UnsupportedScope"
+	ReasonInvalidSpec              = "InvalidSpec"
+	ReasonVerificationFailed       = "VerificationFailed"
+	ReasonNotOwned                 = "NotOwned"
 )
+
+// Suffixes appended to the name of an Account or User to default the names of its Secrets.
+const (
+	JWTSecretNameSuffix         = "-jwt"
+	SeedSecretNameSuffix        = "-seed"
+	CredentialsSecretNameSuffix = "-creds"
+)
+
+// DefaultPushTarget is the name of the push target used when an Operator does not define any PushTargets.
+const DefaultPushTarget = "default"
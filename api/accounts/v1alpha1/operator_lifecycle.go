@@ -62,7 +62,11 @@ func (os *OperatorStatus) MarkSystemAccountResolveUnknown(reason, messageFormat
 	os.MarkSystemAccountUnknown(reason, messageFormat, messageA...)
 }
 
-func (os *OperatorStatus) MarkSystemAccountReady() {
+// MarkSystemAccountReady marks the system account as ready, which requires the system User managed by the controller
+// to also be ready.
+func (os *OperatorStatus) MarkSystemAccountReady(systemUser InferredObjectReference) {
+	os.SystemUserRef = &systemUser
+
 	operatorConditionSet.Manage(os).MarkTrue(OperatorConditionSystemAccountReady)
 }
 
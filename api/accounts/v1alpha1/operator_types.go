@@ -31,12 +31,30 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// TLSConfig is the TLS configuration for communicating to the NATS server for pushing/deleting account JWTs.
-// Initially this only supports defining server-side TLS verification by defining a CA certificate within a secret, in
-// the future we will support mutual-TLS authentication by defining a client certificate and key within a secret.
+// TLSConfig is the TLS configuration for communicating to the NATS server for pushing/deleting account JWTs. All
+// fields are optional, the system trust store is used if CAFile is not set, and a client certificate is only presented
+// if one is configured.
 type TLSConfig struct {
 	// CAFile is a reference to a secret containing the CA certificate to use for TLS connections.
 	CAFile *v1.SecretKeySelector `json:"caFile,omitempty"`
+
+	// CertFile is a reference to a secret containing the client certificate to use for mutual TLS, KeyFile must also be
+	// set.
+	CertFile *v1.SecretKeySelector `json:"certFile,omitempty"`
+
+	// KeyFile is a reference to a secret containing the private key of the client certificate in CertFile.
+	KeyFile *v1.SecretKeySelector `json:"keyFile,omitempty"`
+
+	// ClientCertSecretRef is a reference to a `kubernetes.io/tls` Secret containing the client certificate and key to
+	// use for mutual TLS. This is an alternative to CertFile and KeyFile.
+	ClientCertSecretRef *v1.LocalObjectReference `json:"clientCertSecretRef,omitempty"`
+
+	// ServerName is used to verify the hostname on the NATS server certificate, it defaults to the hostname in the
+	// AccountServerURL.
+	ServerName string `json:"serverName,omitempty"`
+
+	// InsecureSkipVerify disables verification of the NATS server certificate, this should only be used for testing.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
 }
 
 // OperatorSpec defines the desired state of Operator
@@ -74,6 +92,129 @@ type OperatorSpec struct {
 
 	// OperatorServiceURLs is a JWT claim for the Operator
 	OperatorServiceURLs []string `json:"operatorServiceURLs,omitempty"`
+
+	// AssertServerVersion is a JWT claim for the Operator, setting the minimum nats-server version in the form
+	// "major.minor.patch".
+	AssertServerVersion string `json:"assertServerVersion,omitempty"`
+
+	// StrictSigningKeyUsage is a JWT claim for the Operator, when true Accounts and Users must be issued by a signing
+	// key rather than the identity key.
+	StrictSigningKeyUsage bool `json:"strictSigningKeyUsage,omitempty"`
+
+	// Tags is a JWT claim for the Operator.
+	Tags []string `json:"tags,omitempty"`
+
+	// PushTargets are the NATS clusters which Account JWTs are pushed to. When not set, Account JWTs are pushed to a
+	// single target named "default" using AccountServerURL, TLSConfig and the system user credentials.
+	// +optional
+	PushTargets []PushTarget `json:"pushTargets,omitempty"`
+
+	// DisableJWTPush stops Account JWTs from being pushed to any NATS servers, for when the NATS servers resolve
+	// Account JWTs from the built-in account server using `resolver: URL(...)`. PushTargets and PushQuorum are ignored
+	// when this is set.
+	// +optional
+	DisableJWTPush bool `json:"disableJWTPush,omitempty"`
+
+	// PushQuorum is the number of PushTargets which must have been updated before an Account JWT is considered pushed.
+	// This defaults to all targets, values greater than the number of targets also require all targets.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PushQuorum *int32 `json:"pushQuorum,omitempty"`
+
+	// ServerConfig renders a nats-server configuration for this Operator into a ConfigMap, which NATS server pods can
+	// mount directly. It is kept up to date as Accounts change.
+	// +optional
+	ServerConfig *ServerConfig `json:"serverConfig,omitempty"`
+
+	// Prune enables deleting Account JWTs from the PushTargets which do not belong to any Account resource managed by
+	// this Operator, such as Accounts deleted while the controller was not running. Pruning is disabled if not set.
+	// +optional
+	Prune *AccountPruning `json:"prune,omitempty"`
+}
+
+// ResolverType is the type of account resolver used by the NATS servers.
+// +kubebuilder:validation:Enum=full;cache;memory
+type ResolverType string
+
+const (
+	// ResolverTypeFull stores all Account JWTs pushed to the NATS servers on disk.
+	ResolverTypeFull ResolverType = "full"
+
+	// ResolverTypeCache stores a limited number of Account JWTs on disk, fetching others from the other NATS servers.
+	ResolverTypeCache ResolverType = "cache"
+
+	// ResolverTypeMemory preloads all Account JWTs from the configuration, the configuration is updated whenever an
+	// Account JWT changes so the NATS servers must be reloaded to pick up changes.
+	ResolverTypeMemory ResolverType = "memory"
+)
+
+// ServerConfig configures the nats-server configuration rendered for an Operator.
+type ServerConfig struct {
+	// ConfigMapName is the name of the ConfigMap the configuration is written to, under the key "nats.conf". The
+	// configuration contains the operator, system_account and resolver settings and is intended to be included from
+	// the main NATS server configuration.
+	ConfigMapName string `json:"configMapName"`
+
+	// ResolverType is the type of account resolver, it defaults to "full".
+	// +kubebuilder:default=full
+	// +optional
+	ResolverType ResolverType `json:"resolverType,omitempty"`
+
+	// Dir is the directory used to store Account JWTs for the "full" and "cache" resolvers, it defaults to
+	// "/data/resolver".
+	// +optional
+	Dir string `json:"dir,omitempty"`
+
+	// AllowDelete allows Account JWTs to be deleted from a "full" resolver, this is required for Account JWTs to be
+	// deleted when Accounts are deleted or pruned.
+	// +optional
+	AllowDelete bool `json:"allowDelete,omitempty"`
+
+	// Interval is how often a "full" resolver synchronises Account JWTs with the other NATS servers, such as "2m".
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Limit is the maximum number of Account JWTs stored by a "full" or "cache" resolver.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Limit *int32 `json:"limit,omitempty"`
+
+	// TTL is how long a "cache" resolver keeps Account JWTs, such as "2h".
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+}
+
+// AccountPruning configures how unmanaged Account JWTs are pruned from the PushTargets. An unmanaged Account JWT is
+// only deleted once it has been found by two consecutive runs, so that Accounts which are still being created are not
+// pruned.
+type AccountPruning struct {
+	// DryRun reports the unmanaged Account JWTs which would be deleted, as Events and in .status.prune, without
+	// deleting them.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// ExcludedAccounts are the public keys of Accounts which are managed outside of Kubernetes, these are never pruned.
+	// +optional
+	ExcludedAccounts []string `json:"excludedAccounts,omitempty"`
+}
+
+// PushTarget is a NATS cluster which Account JWTs are pushed to.
+type PushTarget struct {
+	// Name identifies the target within the Account status, it must be unique within the Operator.
+	Name string `json:"name"`
+
+	// URL is the NATS server URL to connect to.
+	URL string `json:"url"`
+
+	// TLSConfig is the TLS configuration for communicating with this target.
+	// +optional
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+
+	// CredentialsSecretRef is a reference to a secret containing the credentials of a user in the system account for
+	// this target, the key defaults to "nats.creds". The credentials of the Operator's system user are used if this is
+	// not set.
+	// +optional
+	CredentialsSecretRef *v1.SecretKeySelector `json:"credentialsSecretRef,omitempty"`
 }
 
 // OperatorStatus defines the observed state of Operator
@@ -91,6 +232,39 @@ type OperatorStatus struct {
 	// ResolvedSystemAccount is the Account that this Operator will use as it's system account. This is the same as the
 	// resource defined in OperatorSpec.SystemAccountRef, but validated that the resource exists.
 	ResolvedSystemAccount *InferredObjectReference `json:"resolvedSystemAccount,omitempty"`
+
+	// SystemUserRef is the User created by the controller within the system account. Its credentials Secret is used by
+	// the controller to connect to the NATS servers, and may be mounted by monitoring tools such as nats-surveyor.
+	SystemUserRef *InferredObjectReference `json:"systemUserRef,omitempty"`
+
+	// Prune is the result of the last run pruning unmanaged Account JWTs, it is only set when .spec.prune is set.
+	Prune *AccountPruningStatus `json:"prune,omitempty"`
+}
+
+// AccountPruningStatus is the result of the last run pruning unmanaged Account JWTs.
+type AccountPruningStatus struct {
+	// LastPruneTime is the time at which the PushTargets were last checked for unmanaged Account JWTs.
+	LastPruneTime *metav1.Time `json:"lastPruneTime,omitempty"`
+
+	// DryRun is true if the last run was a dry run. Account JWTs found by a dry run are only deleted once they have
+	// been found again by a run which is not a dry run.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// UnmanagedAccounts are the unmanaged Account JWTs found by the last run.
+	UnmanagedAccounts []UnmanagedAccount `json:"unmanagedAccounts,omitempty"`
+}
+
+// UnmanagedAccount is an Account JWT held by a push target which does not belong to any Account resource.
+type UnmanagedAccount struct {
+	// Target is the name of the push target holding the Account JWT.
+	Target string `json:"target"`
+
+	// PublicKey is the public key of the Account.
+	PublicKey string `json:"publicKey"`
+
+	// Deleted is true if the Account JWT was deleted from the push target, this is false for dry runs or when the
+	// Account JWT was found for the first time.
+	Deleted bool `json:"deleted"`
 }
 
 func (os *OperatorStatus) GetConditions() apis.Conditions {
@@ -103,6 +277,7 @@ func (os *OperatorStatus) SetConditions(conditions apis.Conditions) {
 
 //+genclient
 //+kubebuilder:object:root=true
+//+kubebuilder:storageversion
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Public Key",type=string,JSONPath=`.status.keyPair.publicKey`
 //+kubebuilder:printcolumn:name="System Account",type=string,JSONPath=`.status.resolvedSystemAccount.name`
@@ -1,6 +1,9 @@
 package v1alpha1
 
-import "github.com/versori-oss/nats-account-operator/pkg/apis"
+import (
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 const (
 	SigningKeyConditionReady           = apis.ConditionReady
@@ -8,6 +11,9 @@ const (
 	SigningKeyConditionOwnerResolved   = "OwnerResolved"
 )
 
+// maxRotationHistory is the number of rotations retained in SigningKeyStatus.RotationHistory.
+const maxRotationHistory = 10
+
 var signingKeyConditionSet = apis.NewLivingConditionSet(
 	SigningKeyConditionReady,
 	SigningKeyConditionSeedSecretReady,
@@ -71,3 +77,49 @@ func (s *SigningKeyStatus) MarkOwnerResolveUnknown(reason, messageFormat string,
 
 	signingKeyConditionSet.Manage(s).MarkUnknown(SigningKeyConditionOwnerResolved, reason, messageFormat, messageA...)
 }
+
+// MarkKeyPairRotated replaces the current KeyPair with next. The previous KeyPair is kept as a retiring keypair until
+// retireAt so that the owner continues to trust it during the grace period.
+func (s *SigningKeyStatus) MarkKeyPairRotated(next KeyPair, rotatedAt, retireAt metav1.Time) {
+	if s.KeyPair != nil {
+		s.RetiringKeyPairs = append(s.RetiringKeyPairs, RetiringKeyPair{
+			KeyPair:  *s.KeyPair,
+			RetireAt: retireAt,
+		})
+
+		s.RotationHistory = append(s.RotationHistory, SigningKeyRotationRecord{
+			PreviousPublicKey: s.KeyPair.PublicKey,
+			PublicKey:         next.PublicKey,
+			RotatedAt:         rotatedAt,
+		})
+
+		if len(s.RotationHistory) > maxRotationHistory {
+			s.RotationHistory = s.RotationHistory[len(s.RotationHistory)-maxRotationHistory:]
+		}
+	}
+
+	s.KeyPair = &next
+	s.LastRotationTime = &rotatedAt
+
+	signingKeyConditionSet.Manage(s).MarkTrue(SigningKeyConditionSeedSecretReady)
+}
+
+// MarkKeyPairRetired removes the retiring keypair with the given public key and records the time it was retired in
+// the rotation history.
+func (s *SigningKeyStatus) MarkKeyPairRetired(publicKey string, retiredAt metav1.Time) {
+	retiring := make([]RetiringKeyPair, 0, len(s.RetiringKeyPairs))
+
+	for _, kp := range s.RetiringKeyPairs {
+		if kp.PublicKey != publicKey {
+			retiring = append(retiring, kp)
+		}
+	}
+
+	s.RetiringKeyPairs = retiring
+
+	for i, record := range s.RotationHistory {
+		if record.PreviousPublicKey == publicKey {
+			s.RotationHistory[i].RetiredAt = &retiredAt
+		}
+	}
+}
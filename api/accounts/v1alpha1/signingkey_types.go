@@ -50,6 +50,42 @@ type SigningKeySpec struct {
 	// controller will validate that this SigningKey is allowed to be owned by the referenced resource by evaluating its
 	// label selectors.
 	OwnerRef SigningKeyOwnerReference `json:"ownerRef"`
+
+	// Scope restricts the Users which can be issued by this SigningKey to the role and permissions defined in the
+	// scope. Users issued by a scoped SigningKey take their permissions and limits from the scope template, ignoring
+	// any defined on the User. Scopes are only supported for SigningKeys owned by an Account.
+	// +optional
+	Scope *SigningKeyScope `json:"scope,omitempty"`
+
+	// Rotation defines the policy for automatically rotating the keypair of this SigningKey. When not set the keypair
+	// is never rotated.
+	// +optional
+	Rotation *SigningKeyRotationPolicy `json:"rotation,omitempty"`
+}
+
+// SigningKeyRotationPolicy defines how often a SigningKey is rotated, and for how long the previous keypair remains
+// trusted by the owner after a rotation.
+type SigningKeyRotationPolicy struct {
+	// Interval is the duration between rotations, measured from the time the current keypair was generated.
+	// +required
+	Interval metav1.Duration `json:"interval"`
+
+	// GracePeriod is the duration for which the previous keypair is kept on the owner's JWT after a rotation, this
+	// should be long enough for all dependent Accounts or Users to be re-issued using the new keypair.
+	// +kubebuilder:default="24h"
+	// +optional
+	GracePeriod metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// SigningKeyScope defines the role and permission template for Users issued by a scoped SigningKey.
+type SigningKeyScope struct {
+	// Role is a name describing the role of Users issued by this SigningKey.
+	// +required
+	Role string `json:"role"`
+
+	// Template defines the permissions and limits applied to all Users issued by this SigningKey.
+	// +optional
+	Template UserPermissionLimits `json:"template,omitempty"`
 }
 
 // SigningKeyStatus defines the observed state of SigningKey
@@ -61,10 +97,45 @@ type SigningKeyStatus struct {
 
 	// OwnerRef references the owning object for this signing key. This should be one of Operator or Account.
 	OwnerRef *TypedObjectReference `json:"ownerRef,omitempty"`
+
+	// RetiringKeyPairs contains the previous keypairs of this signing key which are still trusted by the owner
+	// following a rotation. Each is removed once its grace period has passed.
+	RetiringKeyPairs []RetiringKeyPair `json:"retiringKeyPairs,omitempty"`
+
+	// LastRotationTime is the time at which the current keypair replaced the previous one, this is unset if the
+	// keypair has never been rotated.
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+
+	// RotationHistory records the most recent rotations of this signing key, newest last.
+	RotationHistory []SigningKeyRotationRecord `json:"rotationHistory,omitempty"`
+}
+
+// RetiringKeyPair is a previous keypair of a rotated SigningKey.
+type RetiringKeyPair struct {
+	KeyPair `json:",inline"`
+
+	// RetireAt is the time after which this keypair is removed from the owner and its seed secret is deleted.
+	RetireAt metav1.Time `json:"retireAt"`
+}
+
+// SigningKeyRotationRecord records a single rotation of a SigningKey.
+type SigningKeyRotationRecord struct {
+	// PreviousPublicKey is the public key which was replaced.
+	PreviousPublicKey string `json:"previousPublicKey"`
+
+	// PublicKey is the public key which replaced PreviousPublicKey.
+	PublicKey string `json:"publicKey"`
+
+	// RotatedAt is the time of the rotation.
+	RotatedAt metav1.Time `json:"rotatedAt"`
+
+	// RetiredAt is the time PreviousPublicKey was retired, this is unset while it is still within its grace period.
+	RetiredAt *metav1.Time `json:"retiredAt,omitempty"`
 }
 
 //+genclient
 //+kubebuilder:object:root=true
+//+kubebuilder:storageversion
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Public Key",type=string,JSONPath=`.status.keyPair.publicKey`
 //+kubebuilder:printcolumn:name="Owner Kind",type=string,JSONPath=`.status.ownerRef.kind`
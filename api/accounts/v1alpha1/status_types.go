@@ -1,6 +1,10 @@
 package v1alpha1
 
-import "github.com/versori-oss/nats-account-operator/pkg/apis"
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+)
 
 type Status struct {
 	// Conditions the latest available observations of a resource's current state.
@@ -8,6 +12,11 @@ type Status struct {
 	// +patchMergeKey=type
 	// +patchStrategy=merge
 	Conditions apis.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ObservedGeneration is the most recent generation of the resource reconciled by the controller. The conditions
+	// only reflect the current spec when this matches .metadata.generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 var _ apis.ConditionsAccessor = (*Status)(nil)
@@ -20,6 +29,65 @@ func (s *Status) SetConditions(conditions apis.Conditions) {
 	s.Conditions = conditions
 }
 
+// stalledReasons are the reasons for a False Ready condition which the controller cannot recover from until the spec
+// of the resource, or of the resource it references, is changed. Any other reason is reported as Reconciling, since the
+// controller will make progress once a dependency becomes ready or a transient error clears.
+var stalledReasons = map[string]bool{
+	ReasonInvalidSpec:            true,
+	ReasonInvalidLabelSelector:   true,
+	ReasonInvalidSigningKeyOwner: true,
+	ReasonUnsupportedIssuer:      true,
+	ReasonUnsupportedScope:       true,
+	ReasonNotAllowed:             true,
+	ReasonNotOwned:               true,
+}
+
+// ObserveGeneration records generation as reconciled on the status and each of its conditions, and sets the
+// Reconciling and Stalled conditions used by kstatus from the Ready condition. A resource which is not Ready is Stalled
+// if Ready is False with one of the stalledReasons, otherwise it is Reconciling.
+func (s *Status) ObserveGeneration(generation int64) {
+	manager := apis.NewLivingConditionSet().Manage(s)
+	ready := manager.GetTopLevelCondition()
+
+	reconciling := apis.Condition{
+		Type:     apis.ConditionReconciling,
+		Status:   corev1.ConditionFalse,
+		Severity: apis.ConditionSeverityInfo,
+	}
+
+	stalled := apis.Condition{
+		Type:     apis.ConditionStalled,
+		Status:   corev1.ConditionFalse,
+		Severity: apis.ConditionSeverityInfo,
+	}
+
+	switch {
+	case ready.IsTrue():
+	case ready.IsFalse() && stalledReasons[ready.Reason]:
+		stalled.Status = corev1.ConditionTrue
+		stalled.Reason = ready.Reason
+		stalled.Message = ready.Message
+	default:
+		reconciling.Status = corev1.ConditionTrue
+		reconciling.Reason = ReasonNotReady
+		reconciling.Message = "waiting for the resource to become ready"
+
+		if ready != nil && ready.Reason != "" {
+			reconciling.Reason = ready.Reason
+			reconciling.Message = ready.Message
+		}
+	}
+
+	manager.SetCondition(reconciling)
+	manager.SetCondition(stalled)
+
+	s.ObservedGeneration = generation
+
+	for i := range s.Conditions {
+		s.Conditions[i].ObservedGeneration = generation
+	}
+}
+
 // +k8s:deepcopy-gen=false
 
 // StatusAccessor provides a way to access our standard Status subresource which contains Conditions.
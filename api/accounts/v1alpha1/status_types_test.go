@@ -0,0 +1,123 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+)
+
+func TestObserveGeneration(t *testing.T) {
+	tests := []struct {
+		name            string
+		ready           *apis.Condition
+		wantReconciling corev1.ConditionStatus
+		wantStalled     corev1.ConditionStatus
+		wantReason      string
+	}{
+		{
+			name:            "ready",
+			ready:           &apis.Condition{Status: corev1.ConditionTrue},
+			wantReconciling: corev1.ConditionFalse,
+			wantStalled:     corev1.ConditionFalse,
+		},
+		{
+			name:            "no ready condition",
+			wantReconciling: corev1.ConditionTrue,
+			wantStalled:     corev1.ConditionFalse,
+			wantReason:      ReasonNotReady,
+		},
+		{
+			name:            "ready unknown",
+			ready:           &apis.Condition{Status: corev1.ConditionUnknown, Reason: ReasonJWTPushError},
+			wantReconciling: corev1.ConditionTrue,
+			wantStalled:     corev1.ConditionFalse,
+			wantReason:      ReasonJWTPushError,
+		},
+		{
+			name:            "waiting for a dependency to become ready",
+			ready:           &apis.Condition{Status: corev1.ConditionFalse, Reason: ReasonNotReady},
+			wantReconciling: corev1.ConditionTrue,
+			wantStalled:     corev1.ConditionFalse,
+			wantReason:      ReasonNotReady,
+		},
+		{
+			name:            "waiting for a dependency to be created",
+			ready:           &apis.Condition{Status: corev1.ConditionFalse, Reason: ReasonNotFound},
+			wantReconciling: corev1.ConditionTrue,
+			wantStalled:     corev1.ConditionFalse,
+			wantReason:      ReasonNotFound,
+		},
+		{
+			name:            "invalid spec",
+			ready:           &apis.Condition{Status: corev1.ConditionFalse, Reason: ReasonInvalidSpec},
+			wantReconciling: corev1.ConditionFalse,
+			wantStalled:     corev1.ConditionTrue,
+			wantReason:      ReasonInvalidSpec,
+		},
+		{
+			name:            "not allowed by the issuer",
+			ready:           &apis.Condition{Status: corev1.ConditionFalse, Reason: ReasonNotAllowed},
+			wantReconciling: corev1.ConditionFalse,
+			wantStalled:     corev1.ConditionTrue,
+			wantReason:      ReasonNotAllowed,
+		},
+		{
+			name:            "conflicting with a resource it does not own",
+			ready:           &apis.Condition{Status: corev1.ConditionFalse, Reason: ReasonNotOwned},
+			wantReconciling: corev1.ConditionFalse,
+			wantStalled:     corev1.ConditionTrue,
+			wantReason:      ReasonNotOwned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Status{}
+
+			if tt.ready != nil {
+				ready := *tt.ready
+				ready.Type = apis.ConditionReady
+				s.Conditions = apis.Conditions{ready}
+			}
+
+			s.ObserveGeneration(3)
+
+			if s.ObservedGeneration != 3 {
+				t.Errorf("ObservedGeneration = %d, want 3", s.ObservedGeneration)
+			}
+
+			manager := apis.NewLivingConditionSet().Manage(s)
+			reconciling := manager.GetCondition(apis.ConditionReconciling)
+			stalled := manager.GetCondition(apis.ConditionStalled)
+
+			if reconciling == nil || stalled == nil {
+				t.Fatalf("ObserveGeneration() did not set the Reconciling and Stalled conditions: %v", s.Conditions)
+			}
+
+			if reconciling.Status != tt.wantReconciling {
+				t.Errorf("Reconciling = %s, want %s", reconciling.Status, tt.wantReconciling)
+			}
+
+			if stalled.Status != tt.wantStalled {
+				t.Errorf("Stalled = %s, want %s", stalled.Status, tt.wantStalled)
+			}
+
+			reason := reconciling.Reason
+			if stalled.IsTrue() {
+				reason = stalled.Reason
+			}
+
+			if reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+			}
+
+			for _, c := range s.Conditions {
+				if c.ObservedGeneration != 3 {
+					t.Errorf("%s condition ObservedGeneration = %d, want 3", c.Type, c.ObservedGeneration)
+				}
+			}
+		})
+	}
+}
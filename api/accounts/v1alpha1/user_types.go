@@ -37,14 +37,20 @@ type UserSpec struct {
 	// following its namespace and label selector restrictions.
 	Issuer IssuerReference `json:"issuer"`
 
-	// JWTSecretName is the name of the Secret that will be created to store the JWT for this User.
-	JWTSecretName string `json:"jwtSecretName"`
+	// JWTSecretName is the name of the Secret that will be created to store the JWT for this User. Defaults to the
+	// name of the User suffixed with "-jwt".
+	// +optional
+	JWTSecretName string `json:"jwtSecretName,omitempty"`
 
-	// SeedSecretName is the name of the Secret that will be created to store the seed for this User.
-	SeedSecretName string `json:"seedSecretName"`
+	// SeedSecretName is the name of the Secret that will be created to store the seed for this User. Defaults to the
+	// name of the User suffixed with "-seed".
+	// +optional
+	SeedSecretName string `json:"seedSecretName,omitempty"`
 
 	// CredentialsSecretName is the name of the Secret that will be created to store the credentials for this User.
-	CredentialsSecretName string `json:"credentialsSecretName"`
+	// Defaults to the name of the User suffixed with "-creds".
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
 
 	// Permissions is a JWT claim for the User.
 	// +optional
@@ -57,6 +63,15 @@ type UserSpec struct {
 	// BearerToken is a JWT claim for the User.
 	// +optional
 	BearerToken *bool `json:"bearerToken,omitempty"`
+
+	// Expiry is the duration for which the User JWT is valid once issued. When not set the JWT never expires.
+	// +optional
+	Expiry *metav1.Duration `json:"expiry,omitempty"`
+
+	// RenewBefore is how long before the JWT expires that it is re-issued, along with the JWT and credentials Secrets.
+	// This defaults to a third of the Expiry.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
 }
 
 type UserPermissions struct {
@@ -65,6 +80,24 @@ type UserPermissions struct {
 	Resp *RespPermission `json:"resp,omitempty"`
 }
 
+// UserPermissionLimits defines the permissions and limits of a User, this is used as the template for Users issued by
+// a scoped SigningKey.
+type UserPermissionLimits struct {
+	// +optional
+	Permissions *UserPermissions `json:"permissions,omitempty"`
+
+	// +optional
+	Limits UserLimits `json:"limits,omitempty"`
+
+	// +optional
+	BearerToken *bool `json:"bearerToken,omitempty"`
+
+	// AllowedConnectionTypes restricts which connection types the User may use, such as "STANDARD", "WEBSOCKET",
+	// "LEAFNODE" or "MQTT".
+	// +optional
+	AllowedConnectionTypes []string `json:"allowedConnectionTypes,omitempty"`
+}
+
 type Permission struct {
 	Allow []string `json:"allow,omitempty"`
 	Deny  []string `json:"deny,omitempty"`
@@ -98,6 +131,19 @@ type UserStatus struct {
 
 	KeyPair    *KeyPair                 `json:"keyPair,omitempty"`
 	AccountRef *InferredObjectReference `json:"accountRef,omitempty"`
+
+	// IssuedAccountRef is the Account which issued the current User JWT. Unlike AccountRef it is kept when the Account
+	// can no longer be resolved or no longer allows the User, so that the User's public key can still be revoked.
+	IssuedAccountRef *InferredObjectReference `json:"issuedAccountRef,omitempty"`
+
+	// ExpiresAt is the time at which the current User JWT expires, this is unset if the JWT does not expire.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// RenewAt is the time at which the current User JWT will be re-issued.
+	RenewAt *metav1.Time `json:"renewAt,omitempty"`
+
+	// IssuerKey is the public key of the Account or SigningKey which signed the current User JWT.
+	IssuerKey string `json:"issuerKey,omitempty"`
 }
 
 func (s *UserStatus) GetConditions() apis.Conditions {
@@ -110,9 +156,11 @@ func (s *UserStatus) SetConditions(conditions apis.Conditions) {
 
 //+genclient
 //+kubebuilder:object:root=true
+//+kubebuilder:storageversion
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Public Key",type=string,JSONPath=`.status.keyPair.publicKey`
 //+kubebuilder:printcolumn:name="Account",type=string,JSONPath=`.status.accountRef.name`
+//+kubebuilder:printcolumn:name="Expires At",type=string,JSONPath=`.status.expiresAt`,priority=1
 //+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`
 
 // User is the Schema for the users API
@@ -64,6 +64,56 @@ func (in *Account) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountAuthorization) DeepCopyInto(out *AccountAuthorization) {
+	*out = *in
+	if in.AuthUsers != nil {
+		in, out := &in.AuthUsers, &out.AuthUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedAccounts != nil {
+		in, out := &in.AllowedAccounts, &out.AllowedAccounts
+		*out = make([]InferredObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountAuthorization.
+func (in *AccountAuthorization) DeepCopy() *AccountAuthorization {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountAuthorization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountAuthorizationStatus) DeepCopyInto(out *AccountAuthorizationStatus) {
+	*out = *in
+	if in.AuthUsers != nil {
+		in, out := &in.AuthUsers, &out.AuthUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedAccounts != nil {
+		in, out := &in.AllowedAccounts, &out.AllowedAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountAuthorizationStatus.
+func (in *AccountAuthorizationStatus) DeepCopy() *AccountAuthorizationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountAuthorizationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountExport) DeepCopyInto(out *AccountExport) {
 	*out = *in
@@ -87,6 +137,11 @@ func (in *AccountExport) DeepCopy() *AccountExport {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountImport) DeepCopyInto(out *AccountImport) {
 	*out = *in
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountImport.
@@ -171,6 +226,50 @@ func (in *AccountList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountPruning) DeepCopyInto(out *AccountPruning) {
+	*out = *in
+	if in.ExcludedAccounts != nil {
+		in, out := &in.ExcludedAccounts, &out.ExcludedAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountPruning.
+func (in *AccountPruning) DeepCopy() *AccountPruning {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountPruning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountPruningStatus) DeepCopyInto(out *AccountPruningStatus) {
+	*out = *in
+	if in.LastPruneTime != nil {
+		in, out := &in.LastPruneTime, &out.LastPruneTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UnmanagedAccounts != nil {
+		in, out := &in.UnmanagedAccounts, &out.UnmanagedAccounts
+		*out = make([]UnmanagedAccount, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountPruningStatus.
+func (in *AccountPruningStatus) DeepCopy() *AccountPruningStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountPruningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountServiceLatency) DeepCopyInto(out *AccountServiceLatency) {
 	*out = *in
@@ -208,7 +307,9 @@ func (in *AccountSpec) DeepCopyInto(out *AccountSpec) {
 	if in.Imports != nil {
 		in, out := &in.Imports, &out.Imports
 		*out = make([]AccountImport, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Exports != nil {
 		in, out := &in.Exports, &out.Exports
@@ -222,6 +323,26 @@ func (in *AccountSpec) DeepCopyInto(out *AccountSpec) {
 		*out = new(OperatorLimits)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = make(map[string][]WeightedMapping, len(*in))
+		for key, val := range *in {
+			var outVal []WeightedMapping
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]WeightedMapping, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Authorization != nil {
+		in, out := &in.Authorization, &out.Authorization
+		*out = new(AccountAuthorization)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountSpec.
@@ -246,13 +367,38 @@ func (in *AccountStatus) DeepCopyInto(out *AccountStatus) {
 	if in.SigningKeys != nil {
 		in, out := &in.SigningKeys, &out.SigningKeys
 		*out = make([]SigningKeyEmbeddedStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.OperatorRef != nil {
 		in, out := &in.OperatorRef, &out.OperatorRef
 		*out = new(InferredObjectReference)
 		**out = **in
 	}
+	if in.Revocations != nil {
+		in, out := &in.Revocations, &out.Revocations
+		*out = make([]UserRevocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Authorization != nil {
+		in, out := &in.Authorization, &out.Authorization
+		*out = new(AccountAuthorizationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PushTargets != nil {
+		in, out := &in.PushTargets, &out.PushTargets
+		*out = make([]PushTargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastVerificationTime != nil {
+		in, out := &in.LastVerificationTime, &out.LastVerificationTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountStatus.
@@ -404,6 +550,13 @@ func (in *OperatorLimits) DeepCopyInto(out *OperatorLimits) {
 	in.Nats.DeepCopyInto(&out.Nats)
 	in.Account.DeepCopyInto(&out.Account)
 	out.JetStream = in.JetStream
+	if in.TieredLimits != nil {
+		in, out := &in.TieredLimits, &out.TieredLimits
+		*out = make(map[string]JetStreamLimits, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorLimits.
@@ -492,6 +645,33 @@ func (in *OperatorSpec) DeepCopyInto(out *OperatorSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PushTargets != nil {
+		in, out := &in.PushTargets, &out.PushTargets
+		*out = make([]PushTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PushQuorum != nil {
+		in, out := &in.PushQuorum, &out.PushQuorum
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ServerConfig != nil {
+		in, out := &in.ServerConfig, &out.ServerConfig
+		*out = new(ServerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Prune != nil {
+		in, out := &in.Prune, &out.Prune
+		*out = new(AccountPruning)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSpec.
@@ -516,13 +696,25 @@ func (in *OperatorStatus) DeepCopyInto(out *OperatorStatus) {
 	if in.SigningKeys != nil {
 		in, out := &in.SigningKeys, &out.SigningKeys
 		*out = make([]SigningKeyEmbeddedStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ResolvedSystemAccount != nil {
 		in, out := &in.ResolvedSystemAccount, &out.ResolvedSystemAccount
 		*out = new(InferredObjectReference)
 		**out = **in
 	}
+	if in.SystemUserRef != nil {
+		in, out := &in.SystemUserRef, &out.SystemUserRef
+		*out = new(InferredObjectReference)
+		**out = **in
+	}
+	if in.Prune != nil {
+		in, out := &in.Prune, &out.Prune
+		*out = new(AccountPruningStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorStatus.
@@ -560,6 +752,50 @@ func (in *Permission) DeepCopy() *Permission {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushTarget) DeepCopyInto(out *PushTarget) {
+	*out = *in
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushTarget.
+func (in *PushTarget) DeepCopy() *PushTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PushTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushTargetStatus) DeepCopyInto(out *PushTargetStatus) {
+	*out = *in
+	if in.LastPushTime != nil {
+		in, out := &in.LastPushTime, &out.LastPushTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushTargetStatus.
+func (in *PushTargetStatus) DeepCopy() *PushTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PushTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RespPermission) DeepCopyInto(out *RespPermission) {
 	*out = *in
@@ -576,12 +812,49 @@ func (in *RespPermission) DeepCopy() *RespPermission {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetiringKeyPair) DeepCopyInto(out *RetiringKeyPair) {
+	*out = *in
+	out.KeyPair = in.KeyPair
+	in.RetireAt.DeepCopyInto(&out.RetireAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetiringKeyPair.
+func (in *RetiringKeyPair) DeepCopy() *RetiringKeyPair {
+	if in == nil {
+		return nil
+	}
+	out := new(RetiringKeyPair)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerConfig) DeepCopyInto(out *ServerConfig) {
+	*out = *in
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerConfig.
+func (in *ServerConfig) DeepCopy() *ServerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SigningKey) DeepCopyInto(out *SigningKey) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -607,6 +880,11 @@ func (in *SigningKey) DeepCopyObject() runtime.Object {
 func (in *SigningKeyEmbeddedStatus) DeepCopyInto(out *SigningKeyEmbeddedStatus) {
 	*out = *in
 	out.KeyPair = in.KeyPair
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = new(SigningKeyScope)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKeyEmbeddedStatus.
@@ -666,10 +944,73 @@ func (in *SigningKeyOwnerReference) DeepCopy() *SigningKeyOwnerReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningKeyRotationPolicy) DeepCopyInto(out *SigningKeyRotationPolicy) {
+	*out = *in
+	out.Interval = in.Interval
+	out.GracePeriod = in.GracePeriod
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKeyRotationPolicy.
+func (in *SigningKeyRotationPolicy) DeepCopy() *SigningKeyRotationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningKeyRotationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningKeyRotationRecord) DeepCopyInto(out *SigningKeyRotationRecord) {
+	*out = *in
+	in.RotatedAt.DeepCopyInto(&out.RotatedAt)
+	if in.RetiredAt != nil {
+		in, out := &in.RetiredAt, &out.RetiredAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKeyRotationRecord.
+func (in *SigningKeyRotationRecord) DeepCopy() *SigningKeyRotationRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningKeyRotationRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningKeyScope) DeepCopyInto(out *SigningKeyScope) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKeyScope.
+func (in *SigningKeyScope) DeepCopy() *SigningKeyScope {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningKeyScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SigningKeySpec) DeepCopyInto(out *SigningKeySpec) {
 	*out = *in
 	out.OwnerRef = in.OwnerRef
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = new(SigningKeyScope)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(SigningKeyRotationPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKeySpec.
@@ -696,6 +1037,24 @@ func (in *SigningKeyStatus) DeepCopyInto(out *SigningKeyStatus) {
 		*out = new(TypedObjectReference)
 		**out = **in
 	}
+	if in.RetiringKeyPairs != nil {
+		in, out := &in.RetiringKeyPairs, &out.RetiringKeyPairs
+		*out = make([]RetiringKeyPair, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RotationHistory != nil {
+		in, out := &in.RotationHistory, &out.RotationHistory
+		*out = make([]SigningKeyRotationRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKeyStatus.
@@ -753,6 +1112,21 @@ func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
 		*out = new(corev1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CertFile != nil {
+		in, out := &in.CertFile, &out.CertFile
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KeyFile != nil {
+		in, out := &in.KeyFile, &out.KeyFile
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertSecretRef != nil {
+		in, out := &in.ClientCertSecretRef, &out.ClientCertSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
@@ -780,6 +1154,21 @@ func (in *TypedObjectReference) DeepCopy() *TypedObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnmanagedAccount) DeepCopyInto(out *UnmanagedAccount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnmanagedAccount.
+func (in *UnmanagedAccount) DeepCopy() *UnmanagedAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(UnmanagedAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
@@ -865,6 +1254,37 @@ func (in *UserList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserPermissionLimits) DeepCopyInto(out *UserPermissionLimits) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = new(UserPermissions)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Limits.DeepCopyInto(&out.Limits)
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowedConnectionTypes != nil {
+		in, out := &in.AllowedConnectionTypes, &out.AllowedConnectionTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserPermissionLimits.
+func (in *UserPermissionLimits) DeepCopy() *UserPermissionLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(UserPermissionLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UserPermissions) DeepCopyInto(out *UserPermissions) {
 	*out = *in
@@ -887,6 +1307,27 @@ func (in *UserPermissions) DeepCopy() *UserPermissions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserRevocation) DeepCopyInto(out *UserRevocation) {
+	*out = *in
+	in.RevokedAt.DeepCopyInto(&out.RevokedAt)
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	out.UserRef = in.UserRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserRevocation.
+func (in *UserRevocation) DeepCopy() *UserRevocation {
+	if in == nil {
+		return nil
+	}
+	out := new(UserRevocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UserSpec) DeepCopyInto(out *UserSpec) {
 	*out = *in
@@ -902,6 +1343,16 @@ func (in *UserSpec) DeepCopyInto(out *UserSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Expiry != nil {
+		in, out := &in.Expiry, &out.Expiry
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserSpec.
@@ -928,6 +1379,19 @@ func (in *UserStatus) DeepCopyInto(out *UserStatus) {
 		*out = new(InferredObjectReference)
 		**out = **in
 	}
+	if in.IssuedAccountRef != nil {
+		in, out := &in.IssuedAccountRef, &out.IssuedAccountRef
+		*out = new(InferredObjectReference)
+		**out = **in
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.RenewAt != nil {
+		in, out := &in.RenewAt, &out.RenewAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserStatus.
@@ -939,3 +1403,18 @@ func (in *UserStatus) DeepCopy() *UserStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeightedMapping) DeepCopyInto(out *WeightedMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeightedMapping.
+func (in *WeightedMapping) DeepCopy() *WeightedMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(WeightedMapping)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,168 @@
+/*
+MIT License
+
+Copyright (c) 2022 Versori Ltd
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+*/
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// AccountSpec defines the desired state of Account
+type AccountSpec struct {
+	// Issuer is the reference to the Operator or SigningKey that will be used to sign JWTs for this Account. The
+	// controller will check the owner of the SigningKey is an Operator, and that this Account can be managed by that
+	// Operator following its namespace and label selector restrictions.
+	Issuer IssuerReference `json:"issuer"`
+
+	// UsersNamespaceSelector defines which namespaces are allowed to contain Users managed by this Account. The default
+	// restricts to the same namespace as the Account, it can be set to an empty selector `{}` to allow all namespaces.
+	UsersNamespaceSelector *metav1.LabelSelector `json:"usersNamespaceSelector,omitempty"`
+
+	// UsersSelector defines which Users are allowed to be managed by this Account. The default implies no label
+	// selector and all User resources will be allowed (subject to the UsersNamespaceSelector above).
+	UsersSelector *metav1.LabelSelector `json:"usersSelector,omitempty"`
+
+	// JWTSecretName is the name of the Secret that will be created to hold the JWT signing key for this Account.
+	// Defaults to the name of the Account suffixed with "-jwt".
+	// +optional
+	JWTSecretName string `json:"jwtSecretName,omitempty"`
+
+	// SeedSecretName is the name of the Secret that will be created to hold the seed for this Account. Defaults to
+	// the name of the Account suffixed with "-seed".
+	// +optional
+	SeedSecretName string `json:"seedSecretName,omitempty"`
+
+	// SigningKeysSelector is the label selector to restrict which SigningKeys can be used to sign JWTs for this
+	// Account. SigningKeys must be in the same namespace as the Account.
+	SigningKeysSelector *metav1.LabelSelector `json:"signingKeysSelector,omitempty"`
+
+	// Imports is a JWT claim for the Account.
+	Imports []AccountImport `json:"imports,omitempty"`
+
+	// Exports is a JWT claim for the Account.
+	Exports []v1alpha1.AccountExport `json:"exports,omitempty"`
+
+	// Limits is a JWT claim for the Account.
+	Limits *OperatorLimits `json:"limits,omitempty"`
+
+	// Mappings is a JWT claim for the Account, mapping each source subject to one or more weighted destination
+	// subjects. The weights of the destinations for a source subject must not add up to more than 100.
+	Mappings map[string][]v1alpha1.WeightedMapping `json:"mappings,omitempty"`
+
+	// Authorization configures an auth callout service for the Account. The public keys of the referenced Users and
+	// Accounts are resolved from their status and included in the Account JWT.
+	Authorization *v1alpha1.AccountAuthorization `json:"authorization,omitempty"`
+}
+
+// AccountImport imports a stream or service exported by another Account.
+type AccountImport struct {
+	Name    string                    `json:"name"`
+	Subject string                    `json:"subject"`
+	Account string                    `json:"account"`
+	Type    v1alpha1.ImportExportType `json:"type"`
+
+	// Token is the activation token for an import of a private export.
+	// +optional
+	Token *ActivationToken `json:"token,omitempty"`
+
+	// To is the local subject the import is mapped to, defaults to Subject.
+	// +optional
+	To string `json:"to,omitempty"`
+}
+
+// ActivationToken is an activation token set either inline or from a Secret, exactly one of Value and SecretKeyRef
+// must be set.
+type ActivationToken struct {
+	// Value is the activation token JWT.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// SecretKeyRef is a reference to a key in a Secret, in the namespace of the Account, containing the activation
+	// token JWT.
+	// +optional
+	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// OperatorLimits are used to limit access by an account
+type OperatorLimits struct {
+	Nats      v1alpha1.NatsLimits `json:"nats,omitempty"`
+	Account   AccountLimits       `json:"account,omitempty"`
+	JetStream JetStreamLimits     `json:"jetStream,omitempty"`
+
+	// TieredLimits sets JetStream limits per replica tier, keyed by tier name such as "R1" or "R3". This is mutually
+	// exclusive with JetStream.
+	TieredLimits map[string]JetStreamLimits `json:"tieredLimits,omitempty"`
+}
+
+type AccountLimits struct {
+	Imports         *int64 `json:"imports,omitempty"`        // Max number of imports
+	Exports         *int64 `json:"exports,omitempty"`        // Max number of exports
+	WildcardExports *bool  `json:"wildcards,omitempty"`      // Are wildcards allowed in exports
+	DisallowBearer  *bool  `json:"disallowBearer,omitempty"` // User JWT can't be bearer token
+	Conn            *int64 `json:"conn,omitempty"`           // Max number of active connections
+	LeafNodeConn    *int64 `json:"leaf,omitempty"`           // Max number of active leaf node connections
+}
+
+type JetStreamLimits struct {
+	MemoryStorage        *int64 `json:"memoryStorage,omitempty"`        // Max number of bytes stored in memory across all streams. (0 means disabled)
+	DiskStorage          *int64 `json:"diskStorage,omitempty"`          // Max number of bytes stored on disk across all streams. (0 means disabled)
+	Streams              *int64 `json:"streams,omitempty"`              // Max number of streams
+	Consumer             *int64 `json:"consumer,omitempty"`             // Max number of consumers
+	MaxAckPending        *int64 `json:"maxAckPending,omitempty"`        // Max ack pending of a Stream
+	MemoryMaxStreamBytes *int64 `json:"memoryMaxStreamBytes,omitempty"` // Max bytes a memory backed stream can have. (0 means disabled/unlimited)
+	DiskMaxStreamBytes   *int64 `json:"diskMaxStreamBytes,omitempty"`   // Max bytes a disk backed stream can have. (0 means disabled/unlimited)
+	MaxBytesRequired     *bool  `json:"maxBytesRequired,omitempty"`     // Max bytes required by all Streams
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Public Key",type=string,JSONPath=`.status.keyPair.publicKey`
+//+kubebuilder:printcolumn:name="Operator",type=string,JSONPath=`.status.operatorRef.name`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`
+
+// Account is the Schema for the accounts API
+type Account struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AccountSpec            `json:"spec,omitempty"`
+	Status v1alpha1.AccountStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AccountList contains a list of Account
+type AccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Account `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Account{}, &AccountList{})
+}
@@ -0,0 +1,62 @@
+/*
+MIT License
+
+Copyright (c) 2022 Versori Ltd
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ObjectReference references a resource in the accounts.nats.io group in the same namespace as the referencing
+// resource.
+type ObjectReference struct {
+	// APIVersion of the referenced resource, any version of the accounts.nats.io group refers to the same resource.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Kind of the referenced resource.
+	Kind string `json:"kind"`
+
+	// Name of the referenced resource.
+	Name string `json:"name"`
+}
+
+// TypedObjectReference references a resource in the accounts.nats.io group which may be in another namespace.
+type TypedObjectReference struct {
+	ObjectReference `json:",inline"`
+
+	// Namespace of the referenced resource, defaults to the namespace of the referencing resource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// UID of the referenced resource.
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+}
+
+// IssuerReference provides the means to look up a signing key for generating an Account or User.
+type IssuerReference struct {
+	Ref TypedObjectReference `json:"ref"`
+}
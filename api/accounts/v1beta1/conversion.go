@@ -0,0 +1,514 @@
+/*
+MIT License
+
+Copyright (c) 2022 Versori Ltd
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// SpecAnnotation is set on the v1alpha1 version of a resource to preserve parts of its v1beta1 spec which v1alpha1
+// cannot represent.
+const SpecAnnotation = "accounts.nats.io/v1beta1-spec"
+
+var (
+	_ conversion.Convertible = &Account{}
+	_ conversion.Convertible = &Operator{}
+	_ conversion.Convertible = &SigningKey{}
+	_ conversion.Convertible = &User{}
+)
+
+// ConvertTo converts this Account to the hub version.
+func (src *Account) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1alpha1.Account)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 Account but got %T", hub)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertAccountSpecTo(src.Spec)
+	dst.Status = src.Status
+
+	return preserveSpec(&dst.ObjectMeta, src.Spec, convertAccountSpecFrom(dst.Spec))
+}
+
+// ConvertFrom converts the hub version of an Account to this version.
+func (dst *Account) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1alpha1.Account)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 Account but got %T", hub)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertAccountSpecFrom(src.Spec)
+	dst.Status = src.Status
+
+	var preserved AccountSpec
+
+	found, err := restoreSpec(&dst.ObjectMeta, &preserved)
+	if err != nil {
+		return err
+	}
+
+	if found && equality.Semantic.DeepEqual(convertAccountSpecTo(preserved), src.Spec) {
+		dst.Spec = preserved
+	}
+
+	return nil
+}
+
+func convertAccountSpecTo(src AccountSpec) v1alpha1.AccountSpec {
+	dst := v1alpha1.AccountSpec{
+		Issuer:                 convertIssuerReferenceTo(src.Issuer),
+		UsersNamespaceSelector: src.UsersNamespaceSelector,
+		UsersSelector:          src.UsersSelector,
+		JWTSecretName:          src.JWTSecretName,
+		SeedSecretName:         src.SeedSecretName,
+		SigningKeysSelector:    src.SigningKeysSelector,
+		Exports:                src.Exports,
+		Mappings:               src.Mappings,
+		Authorization:          src.Authorization,
+	}
+
+	if src.Imports != nil {
+		dst.Imports = make([]v1alpha1.AccountImport, len(src.Imports))
+
+		for i, imp := range src.Imports {
+			dst.Imports[i] = v1alpha1.AccountImport{
+				Name:    imp.Name,
+				Subject: imp.Subject,
+				Account: imp.Account,
+				To:      imp.To,
+				Type:    imp.Type,
+			}
+
+			if imp.Token != nil {
+				dst.Imports[i].Token = imp.Token.Value
+				dst.Imports[i].TokenSecretRef = imp.Token.SecretKeyRef
+			}
+		}
+	}
+
+	if src.Limits != nil {
+		dst.Limits = &v1alpha1.OperatorLimits{
+			Nats: src.Limits.Nats,
+			Account: v1alpha1.AccountLimits{
+				Imports:         src.Limits.Account.Imports,
+				Exports:         src.Limits.Account.Exports,
+				WildcardExports: src.Limits.Account.WildcardExports,
+				DisallowBearer:  boolValue(src.Limits.Account.DisallowBearer),
+				Conn:            src.Limits.Account.Conn,
+				LeafNodeConn:    src.Limits.Account.LeafNodeConn,
+			},
+			JetStream: convertJetStreamLimitsTo(src.Limits.JetStream),
+		}
+
+		if src.Limits.TieredLimits != nil {
+			dst.Limits.TieredLimits = make(map[string]v1alpha1.JetStreamLimits, len(src.Limits.TieredLimits))
+
+			for tier, limits := range src.Limits.TieredLimits {
+				dst.Limits.TieredLimits[tier] = convertJetStreamLimitsTo(limits)
+			}
+		}
+	}
+
+	return dst
+}
+
+func convertAccountSpecFrom(src v1alpha1.AccountSpec) AccountSpec {
+	dst := AccountSpec{
+		Issuer:                 convertIssuerReferenceFrom(src.Issuer),
+		UsersNamespaceSelector: src.UsersNamespaceSelector,
+		UsersSelector:          src.UsersSelector,
+		JWTSecretName:          src.JWTSecretName,
+		SeedSecretName:         src.SeedSecretName,
+		SigningKeysSelector:    src.SigningKeysSelector,
+		Exports:                src.Exports,
+		Mappings:               src.Mappings,
+		Authorization:          src.Authorization,
+	}
+
+	if src.Imports != nil {
+		dst.Imports = make([]AccountImport, len(src.Imports))
+
+		for i, imp := range src.Imports {
+			dst.Imports[i] = AccountImport{
+				Name:    imp.Name,
+				Subject: imp.Subject,
+				Account: imp.Account,
+				Type:    imp.Type,
+				To:      imp.To,
+			}
+
+			if imp.Token != "" || imp.TokenSecretRef != nil {
+				dst.Imports[i].Token = &ActivationToken{
+					Value:        imp.Token,
+					SecretKeyRef: imp.TokenSecretRef,
+				}
+			}
+		}
+	}
+
+	if src.Limits != nil {
+		dst.Limits = &OperatorLimits{
+			Nats: src.Limits.Nats,
+			Account: AccountLimits{
+				Imports:         src.Limits.Account.Imports,
+				Exports:         src.Limits.Account.Exports,
+				WildcardExports: src.Limits.Account.WildcardExports,
+				DisallowBearer:  boolPtr(src.Limits.Account.DisallowBearer),
+				Conn:            src.Limits.Account.Conn,
+				LeafNodeConn:    src.Limits.Account.LeafNodeConn,
+			},
+			JetStream: convertJetStreamLimitsFrom(src.Limits.JetStream),
+		}
+
+		if src.Limits.TieredLimits != nil {
+			dst.Limits.TieredLimits = make(map[string]JetStreamLimits, len(src.Limits.TieredLimits))
+
+			for tier, limits := range src.Limits.TieredLimits {
+				dst.Limits.TieredLimits[tier] = convertJetStreamLimitsFrom(limits)
+			}
+		}
+	}
+
+	return dst
+}
+
+// ConvertTo converts this Operator to the hub version.
+func (src *Operator) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1alpha1.Operator)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 Operator but got %T", hub)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = src.Spec
+	dst.Status = src.Status
+
+	return nil
+}
+
+// ConvertFrom converts the hub version of an Operator to this version.
+func (dst *Operator) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1alpha1.Operator)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 Operator but got %T", hub)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = src.Spec
+	dst.Status = src.Status
+
+	return nil
+}
+
+// ConvertTo converts this SigningKey to the hub version.
+func (src *SigningKey) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1alpha1.SigningKey)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 SigningKey but got %T", hub)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.SigningKeySpec{
+		Type:           src.Spec.Type,
+		SeedSecretName: src.Spec.SeedSecretName,
+		OwnerRef: v1alpha1.SigningKeyOwnerReference{
+			APIVersion: src.Spec.OwnerRef.APIVersion,
+			Kind:       src.Spec.OwnerRef.Kind,
+			Name:       src.Spec.OwnerRef.Name,
+		},
+		Scope:    src.Spec.Scope,
+		Rotation: src.Spec.Rotation,
+	}
+	dst.Status = src.Status
+
+	return nil
+}
+
+// ConvertFrom converts the hub version of a SigningKey to this version.
+func (dst *SigningKey) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1alpha1.SigningKey)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 SigningKey but got %T", hub)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = SigningKeySpec{
+		Type:           src.Spec.Type,
+		SeedSecretName: src.Spec.SeedSecretName,
+		OwnerRef: ObjectReference{
+			APIVersion: src.Spec.OwnerRef.APIVersion,
+			Kind:       src.Spec.OwnerRef.Kind,
+			Name:       src.Spec.OwnerRef.Name,
+		},
+		Scope:    src.Spec.Scope,
+		Rotation: src.Spec.Rotation,
+	}
+	dst.Status = src.Status
+
+	return nil
+}
+
+// ConvertTo converts this User to the hub version.
+func (src *User) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1alpha1.User)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 User but got %T", hub)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertUserSpecTo(src.Spec)
+	dst.Status = src.Status
+
+	return preserveSpec(&dst.ObjectMeta, src.Spec, convertUserSpecFrom(dst.Spec))
+}
+
+// ConvertFrom converts the hub version of a User to this version.
+func (dst *User) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1alpha1.User)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 User but got %T", hub)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertUserSpecFrom(src.Spec)
+	dst.Status = src.Status
+
+	var preserved UserSpec
+
+	found, err := restoreSpec(&dst.ObjectMeta, &preserved)
+	if err != nil {
+		return err
+	}
+
+	if found && equality.Semantic.DeepEqual(convertUserSpecTo(preserved), src.Spec) {
+		dst.Spec = preserved
+	}
+
+	return nil
+}
+
+func convertUserSpecTo(src UserSpec) v1alpha1.UserSpec {
+	dst := v1alpha1.UserSpec{
+		Issuer:                convertIssuerReferenceTo(src.Issuer),
+		JWTSecretName:         src.JWTSecretName,
+		SeedSecretName:        src.SeedSecretName,
+		CredentialsSecretName: src.CredentialsSecretName,
+		Permissions:           src.Permissions,
+		BearerToken:           src.BearerToken,
+		Expiry:                src.Expiry,
+		RenewBefore:           src.RenewBefore,
+	}
+
+	if src.Limits != nil {
+		dst.Limits = *src.Limits
+	}
+
+	return dst
+}
+
+func convertUserSpecFrom(src v1alpha1.UserSpec) UserSpec {
+	dst := UserSpec{
+		Issuer:                convertIssuerReferenceFrom(src.Issuer),
+		JWTSecretName:         src.JWTSecretName,
+		SeedSecretName:        src.SeedSecretName,
+		CredentialsSecretName: src.CredentialsSecretName,
+		Permissions:           src.Permissions,
+		BearerToken:           src.BearerToken,
+		Expiry:                src.Expiry,
+		RenewBefore:           src.RenewBefore,
+	}
+
+	// v1alpha1 cannot distinguish unset limits from empty limits, both of which are unset in this version unless
+	// preserved by preserveSpec
+	if !equality.Semantic.DeepEqual(src.Limits, v1alpha1.UserLimits{}) {
+		limits := src.Limits
+		dst.Limits = &limits
+	}
+
+	return dst
+}
+
+// preserveSpec stores spec in the SpecAnnotation of the hub if it does not survive conversion to the hub, given the
+// result of converting the hub spec back to this version. This is the case when spec sets values which v1alpha1 does
+// not distinguish from unset values, such as a limit explicitly set to zero.
+func preserveSpec(meta *metav1.ObjectMeta, spec, roundTripped interface{}) error {
+	if equality.Semantic.DeepEqual(spec, roundTripped) {
+		removeAnnotation(meta, SpecAnnotation)
+
+		return nil
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal spec: %w", err)
+	}
+
+	annotations := make(map[string]string, len(meta.Annotations)+1)
+	for k, v := range meta.Annotations {
+		annotations[k] = v
+	}
+
+	annotations[SpecAnnotation] = string(data)
+	meta.Annotations = annotations
+
+	return nil
+}
+
+// restoreSpec removes the SpecAnnotation from meta and unmarshals it into spec, returning false if meta did not have
+// the annotation. The preserved spec must only be used if it still converts to the spec of the hub, otherwise the hub
+// has been changed since it was preserved.
+func restoreSpec(meta *metav1.ObjectMeta, spec interface{}) (bool, error) {
+	data, ok := meta.Annotations[SpecAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	removeAnnotation(meta, SpecAnnotation)
+
+	if err := json.Unmarshal([]byte(data), spec); err != nil {
+		return false, fmt.Errorf("failed to unmarshal %s annotation: %w", SpecAnnotation, err)
+	}
+
+	return true, nil
+}
+
+// removeAnnotation removes key from the annotations of meta without modifying the map it shares with the object it
+// was converted from.
+func removeAnnotation(meta *metav1.ObjectMeta, key string) {
+	if _, ok := meta.Annotations[key]; !ok {
+		return
+	}
+
+	annotations := make(map[string]string, len(meta.Annotations))
+
+	for k, v := range meta.Annotations {
+		if k != key {
+			annotations[k] = v
+		}
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	meta.Annotations = annotations
+}
+
+func convertIssuerReferenceTo(src IssuerReference) v1alpha1.IssuerReference {
+	return v1alpha1.IssuerReference{
+		Ref: v1alpha1.TypedObjectReference{
+			APIVersion: src.Ref.APIVersion,
+			Kind:       src.Ref.Kind,
+			Name:       src.Ref.Name,
+			Namespace:  src.Ref.Namespace,
+			UID:        src.Ref.UID,
+		},
+	}
+}
+
+func convertIssuerReferenceFrom(src v1alpha1.IssuerReference) IssuerReference {
+	return IssuerReference{
+		Ref: TypedObjectReference{
+			ObjectReference: ObjectReference{
+				APIVersion: src.Ref.APIVersion,
+				Kind:       src.Ref.Kind,
+				Name:       src.Ref.Name,
+			},
+			Namespace: src.Ref.Namespace,
+			UID:       src.Ref.UID,
+		},
+	}
+}
+
+func convertJetStreamLimitsTo(src JetStreamLimits) v1alpha1.JetStreamLimits {
+	return v1alpha1.JetStreamLimits{
+		MemoryStorage:        int64Value(src.MemoryStorage),
+		DiskStorage:          int64Value(src.DiskStorage),
+		Streams:              int64Value(src.Streams),
+		Consumer:             int64Value(src.Consumer),
+		MaxAckPending:        int64Value(src.MaxAckPending),
+		MemoryMaxStreamBytes: int64Value(src.MemoryMaxStreamBytes),
+		DiskMaxStreamBytes:   int64Value(src.DiskMaxStreamBytes),
+		MaxBytesRequired:     boolValue(src.MaxBytesRequired),
+	}
+}
+
+func convertJetStreamLimitsFrom(src v1alpha1.JetStreamLimits) JetStreamLimits {
+	return JetStreamLimits{
+		MemoryStorage:        int64Ptr(src.MemoryStorage),
+		DiskStorage:          int64Ptr(src.DiskStorage),
+		Streams:              int64Ptr(src.Streams),
+		Consumer:             int64Ptr(src.Consumer),
+		MaxAckPending:        int64Ptr(src.MaxAckPending),
+		MemoryMaxStreamBytes: int64Ptr(src.MemoryMaxStreamBytes),
+		DiskMaxStreamBytes:   int64Ptr(src.DiskMaxStreamBytes),
+		MaxBytesRequired:     boolPtr(src.MaxBytesRequired),
+	}
+}
+
+// int64Ptr returns nil for zero, which v1alpha1 uses to represent an unset limit. Explicit zeros are preserved by
+// preserveSpec.
+func int64Ptr(v int64) *int64 {
+	if v == 0 {
+		return nil
+	}
+
+	return &v
+}
+
+func int64Value(p *int64) int64 {
+	if p == nil {
+		return 0
+	}
+
+	return *p
+}
+
+// boolPtr returns nil for false, which v1alpha1 uses to represent an unset flag.
+func boolPtr(v bool) *bool {
+	if !v {
+		return nil
+	}
+
+	return &v
+}
+
+func boolValue(p *bool) bool {
+	if p == nil {
+		return false
+	}
+
+	return *p
+}
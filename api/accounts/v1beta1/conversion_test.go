@@ -0,0 +1,192 @@
+/*
+MIT License
+
+Copyright (c) 2022 Versori Ltd
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+*/
+
+package v1beta1
+
+import (
+	"flag"
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	fuzz "github.com/google/gofuzz"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+const fuzzIterations = 1000
+
+var fuzzSeed = flag.Int64("fuzz-seed", 1, "seed for the conversion fuzz tests")
+
+// spoke is a v1beta1 resource along with a new, empty instance of its hub type.
+type spoke struct {
+	name string
+	obj  conversion.Convertible
+	hub  func() conversion.Hub
+}
+
+func spokes() []spoke {
+	return []spoke{
+		{name: "Account", obj: &Account{}, hub: func() conversion.Hub { return &v1alpha1.Account{} }},
+		{name: "Operator", obj: &Operator{}, hub: func() conversion.Hub { return &v1alpha1.Operator{} }},
+		{name: "SigningKey", obj: &SigningKey{}, hub: func() conversion.Hub { return &v1alpha1.SigningKey{} }},
+		{name: "User", obj: &User{}, hub: func() conversion.Hub { return &v1alpha1.User{} }},
+	}
+}
+
+// TestSpokeRoundTrip checks that converting a v1beta1 resource to v1alpha1 and back does not lose any information.
+func TestSpokeRoundTrip(t *testing.T) {
+	for _, s := range spokes() {
+		s := s
+
+		t.Run(s.name, func(t *testing.T) {
+			f := newFuzzer(t)
+
+			for i := 0; i < fuzzIterations; i++ {
+				src := s.obj.DeepCopyObject().(conversion.Convertible)
+				f.Fuzz(src)
+
+				hub := s.hub()
+				if err := src.ConvertTo(hub); err != nil {
+					t.Fatalf("failed to convert to hub: %v", err)
+				}
+
+				dst := s.obj.DeepCopyObject().(conversion.Convertible)
+				if err := dst.ConvertFrom(hub); err != nil {
+					t.Fatalf("failed to convert from hub: %v", err)
+				}
+
+				if !equality.Semantic.DeepEqual(src, dst) {
+					t.Fatalf("round trip through v1alpha1 changed the %s (-want +got):\n%s", s.name, cmp.Diff(src, dst))
+				}
+			}
+		})
+	}
+}
+
+// TestHubRoundTrip checks that converting a v1alpha1 resource to v1beta1 and back does not lose any information.
+func TestHubRoundTrip(t *testing.T) {
+	for _, s := range spokes() {
+		s := s
+
+		t.Run(s.name, func(t *testing.T) {
+			f := newFuzzer(t)
+
+			for i := 0; i < fuzzIterations; i++ {
+				src := s.hub()
+				f.Fuzz(src)
+
+				spoke := s.obj.DeepCopyObject().(conversion.Convertible)
+				if err := spoke.ConvertFrom(src); err != nil {
+					t.Fatalf("failed to convert from hub: %v", err)
+				}
+
+				dst := s.hub()
+				if err := spoke.ConvertTo(dst); err != nil {
+					t.Fatalf("failed to convert to hub: %v", err)
+				}
+
+				if !equality.Semantic.DeepEqual(src, dst) {
+					t.Fatalf("round trip through v1beta1 changed the %s (-want +got):\n%s", s.name, cmp.Diff(src, dst))
+				}
+			}
+		})
+	}
+}
+
+// newFuzzer returns a fuzzer seeded by the -fuzz-seed flag, so that failures can be reproduced.
+func newFuzzer(t *testing.T) *fuzz.Fuzzer {
+	t.Logf("fuzzer seed: %d", *fuzzSeed)
+
+	return fuzz.New().
+		RandSource(rand.NewSource(*fuzzSeed)).
+		NilChance(0.3).
+		NumElements(1, 3).
+		Funcs(
+			// TypeMeta is set by the caller rather than converted
+			func(in *metav1.TypeMeta, c fuzz.Continue) {},
+		)
+}
+
+// TestPreservedSpec checks that values which v1alpha1 does not distinguish from unset values survive a round trip,
+// unless the v1alpha1 resource was changed in the meantime.
+func TestPreservedSpec(t *testing.T) {
+	zero := int64(0)
+
+	src := &Account{
+		Spec: AccountSpec{
+			Imports: []AccountImport{{Name: "import", Token: &ActivationToken{}}},
+			Limits: &OperatorLimits{
+				JetStream: JetStreamLimits{DiskStorage: &zero},
+			},
+		},
+	}
+
+	hub := &v1alpha1.Account{}
+	if err := src.ConvertTo(hub); err != nil {
+		t.Fatalf("failed to convert to hub: %v", err)
+	}
+
+	if _, ok := hub.Annotations[SpecAnnotation]; !ok {
+		t.Fatalf("hub annotations = %v, want %s", hub.Annotations, SpecAnnotation)
+	}
+
+	dst := &Account{}
+	if err := dst.ConvertFrom(hub.DeepCopy()); err != nil {
+		t.Fatalf("failed to convert from hub: %v", err)
+	}
+
+	if diff := cmp.Diff(src, dst); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+
+	// the hub is changed by a v1alpha1 client, so the preserved spec no longer applies
+	hub.Spec.Limits.JetStream.Streams = 10
+
+	dst = &Account{}
+	if err := dst.ConvertFrom(hub); err != nil {
+		t.Fatalf("failed to convert from hub: %v", err)
+	}
+
+	if dst.Spec.Limits.JetStream.DiskStorage != nil || *dst.Spec.Limits.JetStream.Streams != 10 {
+		t.Errorf("converted limits = %+v, want the limits of the changed hub", dst.Spec.Limits.JetStream)
+	}
+
+	if _, ok := dst.Annotations[SpecAnnotation]; ok {
+		t.Errorf("converted annotations = %v, want %s removed", dst.Annotations, SpecAnnotation)
+	}
+
+	// specs which convert without loss are not annotated
+	if err := dst.ConvertTo(hub); err != nil {
+		t.Fatalf("failed to convert to hub: %v", err)
+	}
+
+	if _, ok := hub.Annotations[SpecAnnotation]; ok {
+		t.Errorf("hub annotations = %v, want no %s", hub.Annotations, SpecAnnotation)
+	}
+}
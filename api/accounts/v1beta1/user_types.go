@@ -0,0 +1,105 @@
+/*
+MIT License
+
+Copyright (c) 2022 Versori Ltd
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// UserSpec defines the desired state of User
+type UserSpec struct {
+	// Issuer is the reference to the Issuer that will be used to sign JWTs for this User. The controller
+	// will check the owner of the Issuer is an Account, and that this User can be managed by that Account
+	// following its namespace and label selector restrictions.
+	Issuer IssuerReference `json:"issuer"`
+
+	// JWTSecretName is the name of the Secret that will be created to store the JWT for this User. Defaults to the
+	// name of the User suffixed with "-jwt".
+	// +optional
+	JWTSecretName string `json:"jwtSecretName,omitempty"`
+
+	// SeedSecretName is the name of the Secret that will be created to store the seed for this User. Defaults to the
+	// name of the User suffixed with "-seed".
+	// +optional
+	SeedSecretName string `json:"seedSecretName,omitempty"`
+
+	// CredentialsSecretName is the name of the Secret that will be created to store the credentials for this User.
+	// Defaults to the name of the User suffixed with "-creds".
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+
+	// Permissions is a JWT claim for the User.
+	// +optional
+	Permissions *v1alpha1.UserPermissions `json:"permissions,omitempty"`
+
+	// Limits is a JWT claim for the User.
+	// +optional
+	Limits *v1alpha1.UserLimits `json:"limits,omitempty"`
+
+	// BearerToken is a JWT claim for the User.
+	// +optional
+	BearerToken *bool `json:"bearerToken,omitempty"`
+
+	// Expiry is the duration for which the User JWT is valid once issued. When not set the JWT never expires.
+	// +optional
+	Expiry *metav1.Duration `json:"expiry,omitempty"`
+
+	// RenewBefore is how long before the JWT expires that it is re-issued, along with the JWT and credentials Secrets.
+	// This defaults to a third of the Expiry.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Public Key",type=string,JSONPath=`.status.keyPair.publicKey`
+//+kubebuilder:printcolumn:name="Account",type=string,JSONPath=`.status.accountRef.name`
+//+kubebuilder:printcolumn:name="Expires At",type=string,JSONPath=`.status.expiresAt`,priority=1
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`
+
+// User is the Schema for the users API
+type User struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UserSpec            `json:"spec,omitempty"`
+	Status v1alpha1.UserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// UserList contains a list of User
+type UserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []User `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&User{}, &UserList{})
+}
@@ -28,7 +28,10 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"github.com/nats-io/nats.go"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
 	"github.com/versori-oss/nats-account-operator/controllers/resources"
 	"github.com/versori-oss/nats-account-operator/pkg/helpers"
 	"go.uber.org/multierr"
@@ -41,9 +44,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -60,7 +65,14 @@ const AccountFinalizer = "accounts.nats.io/finalizer"
 type AccountReconciler struct {
 	*BaseReconciler
 	AccountsV1Alpha1 accountsclientsets.AccountsV1alpha1Interface
-	SysAccountLoader *nsc.SystemAccountLoader
+	SysUserLoader    *nsc.SystemUserLoader
+	ClientPool       *nsc.ClientPool
+
+	natsClients *natsClients
+
+	// VerifyInterval is how often the JWTs held by each push target are compared with the Account JWT, any which are
+	// missing or outdated are pushed again. Verification is disabled if this is zero.
+	VerifyInterval time.Duration
 }
 
 //+kubebuilder:rbac:groups=accounts.nats.io,resources=accounts,verbs=get;list;watch;create;update;patch;delete
@@ -92,9 +104,14 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 	acc.Status.InitializeConditions()
 
 	defer func() {
+		acc.Status.ObserveGeneration(acc.Generation)
+
 		if !equality.Semantic.DeepEqual(originalStatus, acc.Status) {
-			if err2 := r.Status().Update(ctx, acc); err2 != nil {
-				logger.Info("failed to update account status", "error", err2.Error(), "account_name", acc.Name, "account_namespace", acc.Namespace)
+			base := acc.DeepCopy()
+			base.Status = *originalStatus
+
+			if err2 := patchAccountStatus(ctx, r.Client, r.APIReader, acc, base); err2 != nil {
+				logger.Info("failed to patch account status", "error", err2.Error(), "account_name", acc.Name, "account_namespace", acc.Namespace)
 
 				err = multierr.Append(err, err2)
 			}
@@ -103,8 +120,7 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 
 	if acc.DeletionTimestamp.IsZero() {
 		if !controllerutil.ContainsFinalizer(acc, AccountFinalizer) {
-			controllerutil.AddFinalizer(acc, AccountFinalizer)
-			if err := r.Update(ctx, acc); err != nil {
+			if err := addFinalizer(ctx, r.Client, r.APIReader, acc, AccountFinalizer); err != nil {
 				return ctrl.Result{}, err
 			}
 		}
@@ -116,8 +132,7 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 
 			logger.V(1).Info("account successfully finalized")
 
-			controllerutil.RemoveFinalizer(acc, AccountFinalizer)
-			if err := r.Update(ctx, acc); err != nil {
+			if err := removeFinalizer(ctx, r.Client, r.APIReader, acc, AccountFinalizer); err != nil {
 				return ctrl.Result{}, err
 			}
 		}
@@ -159,6 +174,11 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		return ctrl.Result{}, err
 	}
 
+	ok, err = r.reconcileAuthorization(ctx, acc)
+	if err != nil || !ok {
+		return ctrl.Result{}, err
+	}
+
 	issuerKP, ok, err := r.loadIssuerSeed(ctx, acc, keyPairable)
 	if err != nil || !ok {
 		if ok {
@@ -173,21 +193,67 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		return ctrl.Result{}, err
 	}
 
+	// drop any revocations which are no longer required before building the JWT, and make sure we come back when the
+	// next one can be pruned
+	result.RequeueAfter = pruneRevocations(acc)
+
 	accountJWT, ok, err := r.reconcileJWTSecret(ctx, acc, issuerKP)
 	if err != nil || !ok {
 		return ctrl.Result{}, err
 	}
 
 	if !helpers.IsSystemAccount(acc, operator) {
-		if err := r.ensureJWTPushed(ctx, acc, operator, issuerKP, accountJWT); err != nil {
+		if err := r.ensureJWTPushed(ctx, acc, operator, accountJWT); err != nil {
 			return ctrl.Result{}, err
 		}
+
+		// come back when the push targets are next due to be verified, unless revocations need pruning sooner
+		if next := r.nextVerification(acc); next > 0 && (result.RequeueAfter == 0 || next < result.RequeueAfter) {
+			result.RequeueAfter = next
+		}
 	} else {
 		// system accounts need the condition to be set to true to enable them to be considered "ready"
 		acc.Status.MarkJWTPushed()
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// pruneRevocations removes any revocations from the Account status where the revoked JWT has since expired, since
+// the JWT would be rejected anyway. It returns the duration until the next revocation can be pruned, or zero if none
+// of the remaining revocations expire.
+func pruneRevocations(acc *v1alpha1.Account) time.Duration {
+	if len(acc.Status.Revocations) == 0 {
+		return 0
+	}
+
+	now := time.Now()
+	revocations := make([]v1alpha1.UserRevocation, 0, len(acc.Status.Revocations))
+
+	var next time.Duration
+
+	for _, revocation := range acc.Status.Revocations {
+		if revocation.ExpiresAt == nil {
+			revocations = append(revocations, revocation)
+
+			continue
+		}
+
+		remaining := revocation.ExpiresAt.Sub(now)
+		if remaining <= 0 {
+			continue
+		}
+
+		revocations = append(revocations, revocation)
+
+		if next == 0 || remaining < next {
+			next = remaining
+		}
+	}
+
+	acc.Status.Revocations = revocations
+
+	return next
 }
 
 // resolveOperator handles the v1alpha1.AccountConditionOperatorResolved condition and updating the
@@ -200,6 +266,12 @@ func (r *AccountReconciler) resolveOperator(ctx context.Context, acc *v1alpha1.A
 	case *v1alpha1.Operator:
 		logger.V(1).Info("account issuer is an operator")
 
+		if v.Spec.StrictSigningKeyUsage {
+			acc.Status.MarkIssuerResolveFailed(v1alpha1.ReasonUnsupportedIssuer, "operator %s/%s requires strict signing key usage, the account must be issued by a SigningKey", v.Namespace, v.Name)
+
+			return nil, false, nil
+		}
+
 		operator = v
 	case *v1alpha1.SigningKey:
 		logger.V(1).Info("account issuer is a signing key, resolving operator")
@@ -213,7 +285,7 @@ func (r *AccountReconciler) resolveOperator(ctx context.Context, acc *v1alpha1.A
 			}
 
 			if ok {
-				return nil, true, nil
+				return nil, false, nil
 			}
 
 			return nil, false, err
@@ -232,6 +304,30 @@ func (r *AccountReconciler) resolveOperator(ctx context.Context, acc *v1alpha1.A
 		return nil, false, nil
 	}
 
+	// the system account is always allowed, regardless of the Operator's selectors
+	if !helpers.IsSystemAccount(acc, operator) {
+		err = r.ensureAllowedBySelectors(ctx, acc, operator.Namespace, operator.Spec.AccountsNamespaceSelector, operator.Spec.AccountsSelector)
+		if err != nil {
+			if cerr, ok := asConditionError(err); ok {
+				cerr.MarkCondition(acc.Status.MarkOperatorResolveFailed, acc.Status.MarkOperatorResolveUnknown)
+
+				if cerr.failure {
+					r.EventRecorder.Eventf(acc, v1.EventTypeWarning, "OperatorRejected", "account rejected by operator %s/%s: %s", operator.Namespace, operator.Name, err.Error())
+
+					if cerr.reason == v1alpha1.ReasonNotAllowed {
+						return nil, false, r.withdrawJWT(ctx, acc, operator)
+					}
+
+					return nil, false, nil
+				}
+			} else {
+				acc.Status.MarkOperatorResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+			}
+
+			return nil, false, err
+		}
+	}
+
 	acc.Status.MarkOperatorResolved(v1alpha1.InferredObjectReference{
 		Namespace: operator.Namespace,
 		Name:      operator.Name,
@@ -411,7 +507,7 @@ func (r *AccountReconciler) ensureSigningKeysUpdated(ctx context.Context, acc *v
 		var err error
 		labelSelector, err = metav1.LabelSelectorAsSelector(acc.Spec.SigningKeysSelector)
 		if err != nil {
-			// TODO: this should be part of a ValidatingAdmissionWebhook and reject if the label selector is invalid
+			// invalid selectors are rejected by the validating webhook, so this only happens if webhooks are disabled
 			r.EventRecorder.Eventf(acc, v1.EventTypeWarning, "InvalidSigningKeysSelector", "Failed to parse label selector: %s", err.Error())
 
 			return err
@@ -434,6 +530,10 @@ func (r *AccountReconciler) ensureSigningKeysUpdated(ctx context.Context, acc *v
 
 	if !equality.Semantic.DeepEqual(acc.Status.SigningKeys, nextSKs) {
 		r.EventRecorder.Event(acc, v1.EventTypeNormal, "SigningKeysChanged", "")
+
+		// Users issued by these signing keys wait for JWTPushed before trusting the keys are known to the account
+		// server, so this must not remain true until the JWT containing them has actually been pushed.
+		acc.Status.MarkJWTPushUnknown(v1alpha1.ReasonNotReady, "signing keys changed, account JWT has not been pushed")
 	}
 
 	acc.Status.MarkSigningKeysUpdated(nextSKs)
@@ -441,14 +541,314 @@ func (r *AccountReconciler) ensureSigningKeysUpdated(ctx context.Context, acc *v
 	return nil
 }
 
+// reconcileAuthorization handles the v1alpha1.AccountConditionAuthorizationResolved condition, resolving the public
+// keys referenced by .spec.authorization into .status.authorization so that they can be included in the Account JWT.
+func (r *AccountReconciler) reconcileAuthorization(ctx context.Context, acc *v1alpha1.Account) (bool, error) {
+	authorization := acc.Spec.Authorization
+	if authorization == nil {
+		acc.Status.MarkAuthorizationResolved(nil)
+
+		return true, nil
+	}
+
+	authUsers, ok, err := r.resolveAuthUsers(ctx, acc)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	allowedAccounts, ok, err := r.resolveAllowedAccounts(ctx, acc)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	var xkey string
+
+	if authorization.XKeySecretName != "" {
+		xkey, ok, err = r.reconcileXKeySecret(ctx, acc)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	acc.Status.MarkAuthorizationResolved(&v1alpha1.AccountAuthorizationStatus{
+		AuthUsers:       authUsers,
+		AllowedAccounts: allowedAccounts,
+		XKey:            xkey,
+	})
+
+	return true, nil
+}
+
+// resolveAuthUsers returns the public keys of the Users referenced by .spec.authorization.authUsers. Each User must
+// either belong to this Account or not have had its Account resolved yet.
+func (r *AccountReconciler) resolveAuthUsers(ctx context.Context, acc *v1alpha1.Account) ([]string, bool, error) {
+	logger := log.FromContext(ctx)
+
+	publicKeys := make([]string, 0, len(acc.Spec.Authorization.AuthUsers))
+
+	for _, name := range acc.Spec.Authorization.AuthUsers {
+		usr := new(v1alpha1.User)
+
+		err := r.Client.Get(ctx, client.ObjectKey{Namespace: acc.Namespace, Name: name}, usr)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// we'll be enqueued again when the User is created
+				acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonNotFound, "auth user %q not found", name)
+
+				return nil, false, nil
+			}
+
+			logger.Error(err, "failed to get auth user", "user", name)
+
+			acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+			return nil, false, err
+		}
+
+		if ref := usr.Status.AccountRef; ref != nil && (ref.Namespace != acc.Namespace || ref.Name != acc.Name) {
+			acc.Status.MarkAuthorizationResolveFailed(v1alpha1.ReasonInvalidSpec, "auth user %q belongs to account %s/%s", name, ref.Namespace, ref.Name)
+
+			return nil, false, nil
+		}
+
+		if usr.Status.KeyPair == nil {
+			acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonNotReady, "auth user %q does not have a keypair", name)
+
+			return nil, false, nil
+		}
+
+		publicKeys = append(publicKeys, usr.Status.KeyPair.PublicKey)
+	}
+
+	return publicKeys, true, nil
+}
+
+// resolveAllowedAccounts returns the public keys of the Accounts referenced by .spec.authorization.allowedAccounts.
+func (r *AccountReconciler) resolveAllowedAccounts(ctx context.Context, acc *v1alpha1.Account) ([]string, bool, error) {
+	logger := log.FromContext(ctx)
+
+	if len(acc.Spec.Authorization.AllowedAccounts) == 0 {
+		return nil, true, nil
+	}
+
+	publicKeys := make([]string, 0, len(acc.Spec.Authorization.AllowedAccounts))
+
+	for _, ref := range acc.Spec.Authorization.AllowedAccounts {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = acc.Namespace
+		}
+
+		allowed := new(v1alpha1.Account)
+
+		err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, allowed)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// we'll be enqueued again when the Account is created
+				acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonNotFound, "allowed account %s/%s not found", namespace, ref.Name)
+
+				return nil, false, nil
+			}
+
+			logger.Error(err, "failed to get allowed account", "account_name", ref.Name, "account_namespace", namespace)
+
+			acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+			return nil, false, err
+		}
+
+		if allowed.Status.KeyPair == nil {
+			acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonNotReady, "allowed account %s/%s does not have a keypair", namespace, ref.Name)
+
+			return nil, false, nil
+		}
+
+		publicKeys = append(publicKeys, allowed.Status.KeyPair.PublicKey)
+	}
+
+	return publicKeys, true, nil
+}
+
+// reconcileXKeySecret ensures the Secret named by .spec.authorization.xKeySecretName holds a curve keypair, creating it
+// if it doesn't exist, and returns the public key.
+func (r *AccountReconciler) reconcileXKeySecret(ctx context.Context, acc *v1alpha1.Account) (string, bool, error) {
+	logger := log.FromContext(ctx)
+
+	got, err := r.CoreV1.Secrets(acc.Namespace).Get(ctx, acc.Spec.Authorization.XKeySecretName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("xkey secret not found, generating new curve keypair")
+
+			return r.createXKeySecret(ctx, acc)
+		}
+
+		logger.Error(err, "failed to get xkey secret")
+
+		acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+		return "", false, err
+	}
+
+	seed, ok := got.Data[v1alpha1.NatsSecretSeedKey]
+	if !ok {
+		acc.Status.MarkAuthorizationResolveFailed(v1alpha1.ReasonInvalidSeedSecret, "xkey secret does not contain seed data, delete the secret for a new keypair")
+
+		return "", false, nil
+	}
+
+	kp, err := nkeys.FromCurveSeed(seed)
+	if err != nil {
+		acc.Status.MarkAuthorizationResolveFailed(v1alpha1.ReasonInvalidSeedSecret, "failed to parse xkey seed: %s", err.Error())
+
+		return "", false, nil
+	}
+
+	want, err := resources.NewXKeySecretBuilderFromSecret(got.DeepCopy(), r.Scheme).Build(acc, kp)
+	if err != nil {
+		logger.Error(err, "failed to build desired xkey secret")
+
+		acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+		return "", false, err
+	}
+
+	if !equality.Semantic.DeepEqual(got, want) {
+		logger.V(1).Info("xkey secret does not match desired state, updating")
+
+		if err = r.Client.Update(ctx, want); err != nil {
+			logger.Error(err, "failed to update xkey secret")
+
+			acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+			return "", false, err
+		}
+
+		r.EventRecorder.Eventf(acc, v1.EventTypeNormal, "XKeySecretUpdated", "updated secret: %s/%s", want.Namespace, want.Name)
+	}
+
+	pubkey, _ := kp.PublicKey()
+
+	return pubkey, true, nil
+}
+
+func (r *AccountReconciler) createXKeySecret(ctx context.Context, acc *v1alpha1.Account) (string, bool, error) {
+	logger := log.FromContext(ctx)
+
+	kp, err := nkeys.CreateCurveKeys()
+	if err != nil {
+		logger.Error(err, "failed to create xkey keypair")
+
+		acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+		return "", false, err
+	}
+
+	secret, err := resources.NewXKeySecretBuilder(r.Scheme).Build(acc, kp)
+	if err != nil {
+		logger.Error(err, "failed to build xkey secret")
+
+		acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+		return "", false, err
+	}
+
+	if err = controllerutil.SetControllerReference(acc, secret, r.Scheme); err != nil {
+		logger.Error(err, "failed to set xkey secret controller reference")
+
+		acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+		return "", false, err
+	}
+
+	if err = r.Client.Create(ctx, secret); err != nil {
+		logger.Error(err, "failed to create xkey secret")
+
+		acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+		return "", false, err
+	}
+
+	r.EventRecorder.Eventf(acc, v1.EventTypeNormal, "XKeySecretCreated", "created secret: %s/%s", secret.Namespace, secret.Name)
+
+	pubkey, err := kp.PublicKey()
+	if err != nil {
+		logger.Error(err, "failed to get xkey public key")
+
+		acc.Status.MarkAuthorizationResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+		return "", false, err
+	}
+
+	return pubkey, true, nil
+}
+
+// resolveImportTokens returns acc with the activation tokens referenced by .spec.imports[].tokenSecretRef read into
+// .spec.imports[].token, so that the Account claims can be created from the spec. acc is copied if any token is read
+// from a Secret. Changes to the Secrets are picked up the next time the Account is reconciled.
+func (r *AccountReconciler) resolveImportTokens(ctx context.Context, acc *v1alpha1.Account) (*v1alpha1.Account, error) {
+	resolved := acc
+
+	for i, imp := range acc.Spec.Imports {
+		ref := imp.TokenSecretRef
+		if ref == nil {
+			continue
+		}
+
+		if resolved == acc {
+			resolved = acc.DeepCopy()
+		}
+
+		optional := ref.Optional != nil && *ref.Optional
+
+		secret, err := r.CoreV1.Secrets(acc.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) && optional {
+				resolved.Spec.Imports[i].Token = ""
+
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to get activation token for import %q: %w", imp.Name, err)
+		}
+
+		token, ok := secret.Data[ref.Key]
+		if !ok && !optional {
+			return nil, errors.NewNotFound(v1.Resource("secrets"), fmt.Sprintf("%s[%s]", ref.Name, ref.Key))
+		}
+
+		resolved.Spec.Imports[i].Token = strings.TrimSpace(string(token))
+	}
+
+	return resolved, nil
+}
+
 func (r *AccountReconciler) reconcileJWTSecret(ctx context.Context, acc *v1alpha1.Account, issuerKP nkeys.KeyPair) (ajwt string, ok bool, err error) {
 	logger := log.FromContext(ctx)
 
 	// we want to check that any existing secret decodes to match wantClaims, if it doesn't then we will use nextJWT
 	// to create/update the secret. We cannot just compare the JWTs from the secret and accountJWT because the JWTs are
 	// timestamped with the `iat` claim so will never match.
-	wantClaims, nextJWT, err := nsc.CreateAccountClaims(acc, issuerKP)
+	resolved, err := r.resolveImportTokens(ctx, acc)
+	if err != nil {
+		reason := v1alpha1.ReasonUnknownError
+		if errors.IsNotFound(err) {
+			reason = v1alpha1.ReasonNotFound
+		}
+
+		acc.Status.MarkJWTSecretFailed(reason, err.Error())
+
+		return "", false, err
+	}
+
+	wantClaims, nextJWT, err := nsc.CreateAccountClaims(resolved, issuerKP)
 	if err != nil {
+		if isInvalidSpec(err) {
+			// retrying won't help, we need to wait for the spec to be fixed
+			acc.Status.MarkJWTSecretFailed(v1alpha1.ReasonInvalidSpec, err.Error())
+
+			return "", false, nil
+		}
+
 		acc.Status.MarkJWTSecretFailed(v1alpha1.ReasonUnknownError, err.Error())
 
 		return "", false, err
@@ -639,51 +1039,235 @@ func (r *AccountReconciler) ensureJWTSecretUpToDate(ctx context.Context, acc *v1
 	return nextJWT, true, nil
 }
 
-func (r *AccountReconciler) ensureJWTPushed(ctx context.Context, acc *v1alpha1.Account, operator *v1alpha1.Operator, issuer nkeys.KeyPair, ajwt string) error {
+// ensureJWTPushed pushes the Account JWT to each of the Operator's push targets, recording the result for each target
+// in .status.pushTargets. The JWTPushed condition is true once the Operator's push quorum has been met, but failures
+// for any remaining targets are still returned so that they are retried.
+//
+// When verification is due the JWT held by each target is looked up first, and only pushed if it is missing or
+// outdated. Targets which have lost a JWT that was previously pushed are reported with a JWTDriftDetected event.
+func (r *AccountReconciler) ensureJWTPushed(ctx context.Context, acc *v1alpha1.Account, operator *v1alpha1.Operator, ajwt string) error {
 	logger := log.FromContext(ctx)
 
-	sysSeed, err := r.SysAccountLoader.Load(ctx, operator)
+	claims, err := jwt.DecodeAccountClaims(ajwt)
 	if err != nil {
-		logger.Error(err, "failed to load system account")
-
-		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonUnknownError, err.Error())
+		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonInvalidJWTSecret, "failed to decode account JWT: %s", err.Error())
 
 		return err
 	}
 
-	opts, err := r.getNATSOptions(ctx, operator)
-	if err != nil {
-		logger.Error(err, "failed to get NATS options")
+	targets := helpers.PushTargets(operator)
+	if len(targets) == 0 {
+		// pushing is disabled, the NATS servers fetch the JWT from the account server instead
+		acc.Status.PushTargets = nil
+		acc.Status.MarkJWTPushed()
 
-		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonUnknownError, err.Error())
+		return nil
+	}
 
-		return err
+	if err := validatePushTargets(targets); err != nil {
+		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonInvalidSpec, err.Error())
+
+		return nil
 	}
 
-	nscClient, err := nsc.Connect(operator.Spec.AccountServerURL, issuer, sysSeed, opts...)
-	if err != nil {
-		logger.Error(err, "failed to connect to account server")
+	quorum := helpers.PushQuorum(operator, len(targets))
+	verify := r.verificationDue(acc)
 
-		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonUnknownError, err.Error())
+	statuses := make([]v1alpha1.PushTargetStatus, len(targets))
 
-		return err
+	var (
+		pushed     int
+		errs       error
+		retries    bool
+		drifted    []string
+		unverified []string
+	)
+
+	for i, target := range targets {
+		statuses[i] = previousPushTargetStatus(acc.Status.PushTargets, target.Name)
+
+		upToDate := statuses[i].Pushed && statuses[i].JWTID == claims.ID
+
+		lookup, err := r.syncToTarget(ctx, operator, target, claims.Subject, ajwt, verify)
+
+		switch {
+		case lookup == lookupFailed:
+			unverified = append(unverified, target.Name)
+		case lookup == lookupMismatch && upToDate:
+			// the target has lost the JWT we previously pushed, e.g. the resolver directory was wiped
+			drifted = append(drifted, target.Name)
+		}
+
+		if err != nil {
+			logger.Error(err, "failed to push account JWT to account server", "target", target.Name)
+
+			statuses[i].Pushed = false
+			statuses[i].Message = err.Error()
+
+			errs = multierr.Append(errs, fmt.Errorf("%s: %w", target.Name, err))
+
+			// retrying won't help for an invalid spec, we need to wait for the Operator spec to be fixed
+			retries = retries || !isInvalidSpec(err)
+
+			continue
+		}
+
+		pushed++
+
+		if statuses[i].JWTID != claims.ID {
+			now := metav1.Now()
+
+			statuses[i].JWTID = claims.ID
+			statuses[i].LastPushTime = &now
+		}
+
+		statuses[i].Pushed = true
+		statuses[i].Message = ""
 	}
 
-	defer nscClient.Close()
+	acc.Status.PushTargets = statuses
+
+	if len(drifted) > 0 {
+		r.EventRecorder.Eventf(acc, v1.EventTypeWarning, "JWTDriftDetected", "account JWT missing or outdated on push targets: %s", strings.Join(drifted, ", "))
+	}
+
+	if verify {
+		now := metav1.Now()
+		acc.Status.LastVerificationTime = &now
+
+		switch {
+		case errs != nil:
+			acc.Status.MarkInSyncFailed(v1alpha1.ReasonJWTPushError, errs.Error())
+		case len(unverified) > 0:
+			acc.Status.MarkInSyncUnknown(v1alpha1.ReasonVerificationFailed, "failed to look up account JWT from push targets: %s", strings.Join(unverified, ", "))
+		default:
+			acc.Status.MarkInSync()
+		}
+	}
+
+	switch {
+	case pushed >= quorum:
+		acc.Status.MarkJWTPushed()
+	case !retries:
+		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonInvalidSpec, "pushed to %d of %d targets, %d required: %s", pushed, len(targets), quorum, errs.Error())
+	default:
+		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonJWTPushError, "pushed to %d of %d targets, %d required: %s", pushed, len(targets), quorum, errs.Error())
+	}
+
+	if !retries {
+		return nil
+	}
+
+	return errs
+}
+
+// lookupResult describes the outcome of comparing the JWT held by a push target with the Account JWT.
+type lookupResult int
+
+const (
+	// lookupSkipped means the JWT was pushed without looking it up first.
+	lookupSkipped lookupResult = iota
+
+	// lookupMatch means the push target already held the Account JWT.
+	lookupMatch
+
+	// lookupMismatch means the push target held a different JWT, or none at all.
+	lookupMismatch
+
+	// lookupFailed means the JWT could not be looked up, so it was pushed regardless.
+	lookupFailed
+)
+
+// syncToTarget makes sure the push target holds the Account JWT. When verify is true the JWT held by the target is
+// looked up first so that it is only pushed if missing or outdated, otherwise it is pushed unconditionally. If the
+// lookup times out the target is treated as unreachable and the JWT is not pushed.
+func (r *AccountReconciler) syncToTarget(ctx context.Context, operator *v1alpha1.Operator, target v1alpha1.PushTarget, accountID, ajwt string, verify bool) (lookupResult, error) {
+	nscClient, err := r.natsClients.get(ctx, operator, target)
+	if err != nil {
+		return lookupSkipped, err
+	}
+
+	lookup := lookupSkipped
+
+	if verify {
+		current, err := nscClient.Lookup(ctx, accountID)
+
+		switch {
+		case isRequestTimeout(err):
+			// the target is unreachable, so don't wait for the push to time out as well, the target is counted as
+			// failed towards the push quorum
+			r.natsClients.invalidate(nscClient, operator, target)
+
+			return lookupFailed, err
+		case err != nil:
+			log.FromContext(ctx).Info("failed to look up account JWT", "target", target.Name, "error", err.Error())
+
+			lookup = lookupFailed
+		case current == ajwt:
+			return lookupMatch, nil
+		default:
+			lookup = lookupMismatch
+		}
+	}
 
 	if err = nscClient.Push(ctx, ajwt); err != nil {
-		logger.Error(err, "failed to push account JWT to account server")
+		r.natsClients.invalidate(nscClient, operator, target)
 
-		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonJWTPushError, err.Error())
+		return lookup, err
+	}
 
-		return err
+	return lookup, nil
+}
+
+// verificationDue returns true if the JWTs held by the push targets should be compared with the Account JWT, this is
+// never due if verification is disabled.
+func (r *AccountReconciler) verificationDue(acc *v1alpha1.Account) bool {
+	return r.VerifyInterval > 0 && r.nextVerification(acc) == 0
+}
+
+// nextVerification returns the duration until the next verification is due, or zero if it is due now or verification
+// is disabled.
+func (r *AccountReconciler) nextVerification(acc *v1alpha1.Account) time.Duration {
+	if r.VerifyInterval <= 0 || acc.Status.LastVerificationTime == nil {
+		return 0
+	}
+
+	remaining := time.Until(acc.Status.LastVerificationTime.Add(r.VerifyInterval))
+	if remaining < 0 {
+		return 0
 	}
 
-	acc.Status.MarkJWTPushed()
+	return remaining
+}
+
+// validatePushTargets returns an ErrInvalidSpec error if any of the push targets share a name, since the name is used
+// to identify the target in the Account status and the connection pool.
+func validatePushTargets(targets []v1alpha1.PushTarget) error {
+	names := make(map[string]struct{}, len(targets))
+
+	for _, target := range targets {
+		if _, ok := names[target.Name]; ok {
+			return fmt.Errorf("%w: duplicate push target name %q", nsc.ErrInvalidSpec, target.Name)
+		}
+
+		names[target.Name] = struct{}{}
+	}
 
 	return nil
 }
 
+// previousPushTargetStatus returns a copy of the status for the named push target, or a new status if the target has
+// not been pushed to before.
+func previousPushTargetStatus(statuses []v1alpha1.PushTargetStatus, name string) v1alpha1.PushTargetStatus {
+	for _, status := range statuses {
+		if status.Name == name {
+			return *status.DeepCopy()
+		}
+	}
+
+	return v1alpha1.PushTargetStatus{Name: name}
+}
+
 func (r *AccountReconciler) finalizeAccount(ctx context.Context, acc *v1alpha1.Account) error {
 	logger := log.FromContext(ctx)
 
@@ -706,11 +1290,10 @@ func (r *AccountReconciler) finalizeAccount(ctx context.Context, acc *v1alpha1.A
 	//  If the user really wanted to circumvent this, they could force delete and remove the finalizer - at which point
 	//  "if the user wants to do that, then it's their *** fault".
 
-	operatorRef := acc.Status.OperatorRef
-	operator, err := r.AccountsV1Alpha1.Operators(operatorRef.Namespace).Get(ctx, operatorRef.Name, metav1.GetOptions{})
+	operator, err := r.finalizationOperator(ctx, acc)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			logger.Info("operator not found, skipping finalization")
+		if cerr, ok := asConditionError(err); errors.IsNotFound(err) || (ok && cerr.failure) {
+			logger.Info("operator not found, skipping finalization", "reason", err.Error())
 
 			return nil
 		}
@@ -720,114 +1303,224 @@ func (r *AccountReconciler) finalizeAccount(ctx context.Context, acc *v1alpha1.A
 		return fmt.Errorf("operator could not be loaded: %w", err)
 	}
 
+	operatorKP, err := r.loadOperatorKeyPair(ctx, operator)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+
+	for _, target := range helpers.PushTargets(operator) {
+		if err := r.deleteFromTarget(ctx, operator, target, operatorKP, acc.Status.KeyPair.PublicKey); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("%s: %w", target.Name, err))
+		}
+	}
+
+	return errs
+}
+
+// finalizationOperator returns the Operator which the Account JWT was pushed for. This is normally the Operator in
+// .status.operatorRef, but that is cleared whenever the Operator cannot be resolved or no longer allows the Account, in
+// which case the Operator is resolved from .spec.issuer instead.
+func (r *AccountReconciler) finalizationOperator(ctx context.Context, acc *v1alpha1.Account) (*v1alpha1.Operator, error) {
+	if ref := acc.Status.OperatorRef; ref != nil {
+		return r.AccountsV1Alpha1.Operators(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	}
+
+	keyPair, _, err := r.resolveIssuer(ctx, acc.Spec.Issuer, acc.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := keyPair.(type) {
+	case *v1alpha1.Operator:
+		return v, nil
+	case *v1alpha1.SigningKey:
+		owner, _, err := r.resolveSigningKeyOwner(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+
+		if operator, ok := owner.(*v1alpha1.Operator); ok {
+			return operator, nil
+		}
+
+		return nil, ConditionFailed(v1alpha1.ReasonInvalidSigningKeyOwner, "account issuer is not owned by an Operator, got: %s", owner.GetObjectKind().GroupVersionKind().String())
+	default:
+		return nil, ConditionFailed(v1alpha1.ReasonUnsupportedIssuer, "invalid keypair, expected Operator or SigningKey, got: %s", keyPair.GroupVersionKind().String())
+	}
+}
+
+// loadOperatorKeyPair loads the identity keypair of the Operator, which is required to delete Account JWTs from its
+// push targets.
+func (r *AccountReconciler) loadOperatorKeyPair(ctx context.Context, operator *v1alpha1.Operator) (nkeys.KeyPair, error) {
 	if operator.Status.KeyPair == nil {
-		return fmt.Errorf("operator not ready")
+		return nil, fmt.Errorf("operator not ready")
 	}
 
 	operatorSeed, err := r.CoreV1.Secrets(operator.Namespace).Get(ctx, operator.Status.KeyPair.SeedSecretName, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("unable to load operator seed: %w", err)
+		return nil, fmt.Errorf("unable to load operator seed: %w", err)
 	}
 
 	operatorSeedData, ok := operatorSeed.Data[v1alpha1.NatsSecretSeedKey]
 	if !ok {
-		return fmt.Errorf("operator seed secret missing property, %q", v1alpha1.NatsSecretSeedKey)
+		return nil, fmt.Errorf("operator seed secret missing property, %q", v1alpha1.NatsSecretSeedKey)
 	}
 
 	operatorKP, err := nkeys.FromSeed(operatorSeedData)
 	if err != nil {
-		return fmt.Errorf("failed to parse operator seed data: %w", err)
+		return nil, fmt.Errorf("failed to parse operator seed data: %w", err)
 	}
 
-	sysSeed, err := r.SysAccountLoader.Load(ctx, operator)
-	if err != nil {
-		// not sure what errors should allow finalization to skip vs fail for a retry, for now we'll only skip if the
-		// system account doesn't exist, otherwise we'll fail for a retry
-		if errors.IsNotFound(err) {
-			logger.Info("system account not found, skipping finalization")
+	return operatorKP, nil
+}
 
-			return nil
+// withdrawJWT deletes the Account JWT from the Operator's push targets once the Operator no longer allows the Account,
+// so that its Users can no longer connect. Only targets which the JWT was pushed to are contacted, and JWTPushed is
+// marked False once the JWT has been deleted from all of them. Failed deletes are returned so that they are retried.
+func (r *AccountReconciler) withdrawJWT(ctx context.Context, acc *v1alpha1.Account, operator *v1alpha1.Operator) error {
+	pushed := acc.Status.GetCondition(v1alpha1.AccountConditionJWTPushed).IsTrue()
+
+	targets := helpers.PushTargets(operator)
+	statuses := make([]v1alpha1.PushTargetStatus, 0, len(targets))
+	withdraw := make([]int, 0, len(targets))
+
+	for _, target := range targets {
+		status := previousPushTargetStatus(acc.Status.PushTargets, target.Name)
+
+		if pushed || status.Pushed {
+			withdraw = append(withdraw, len(statuses))
 		}
 
-		logger.Error(err, "failed to load system account during finalization")
+		statuses = append(statuses, status)
+	}
 
-		return err
+	if len(withdraw) == 0 || acc.Status.KeyPair == nil {
+		return nil
 	}
 
-	opts, err := r.getNATSOptions(ctx, operator)
+	operatorKP, err := r.loadOperatorKeyPair(ctx, operator)
 	if err != nil {
-		logger.Error(err, "failed to get NATS options")
-
-		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonUnknownError, err.Error())
+		acc.Status.MarkJWTPushUnknown(v1alpha1.ReasonIssuerSeedError, "failed to delete account JWT: %s", err.Error())
 
 		return err
 	}
 
-	nscClient, err := nsc.Connect(operator.Spec.AccountServerURL, operatorKP, sysSeed, opts...)
-	if err != nil {
-		logger.Error(err, "failed to connect to account server during finalization")
+	var errs error
 
-		return err
+	for _, i := range withdraw {
+		target := targets[i]
+
+		if err := r.deleteFromTarget(ctx, operator, target, operatorKP, acc.Status.KeyPair.PublicKey); err != nil {
+			statuses[i].Message = err.Error()
+
+			errs = multierr.Append(errs, fmt.Errorf("%s: %w", target.Name, err))
+
+			continue
+		}
+
+		statuses[i].Pushed = false
+		statuses[i].Message = "deleted, account is not allowed by the operator"
 	}
 
-	defer nscClient.Close()
+	acc.Status.PushTargets = statuses
 
-	if err = nscClient.Delete(ctx, acc.Status.KeyPair.PublicKey); err != nil {
-		logger.Error(err, "failed to delete account JWT")
+	if errs != nil {
+		acc.Status.MarkJWTPushUnknown(v1alpha1.ReasonJWTPushError, "failed to delete account JWT: %s", errs.Error())
 
-		return err
+		return errs
 	}
 
+	acc.Status.MarkJWTPushFailed(v1alpha1.ReasonNotAllowed, "account JWT deleted, account is not allowed by operator %s/%s", operator.Namespace, operator.Name)
+
+	r.EventRecorder.Eventf(acc, v1.EventTypeWarning, "JWTDeleted", "deleted account JWT from push targets, account is not allowed by operator %s/%s", operator.Namespace, operator.Name)
+
 	return nil
 }
 
-func (r *AccountReconciler) getNATSOptions(ctx context.Context, operator *v1alpha1.Operator) ([]nats.Option, error) {
-	if operator.Spec.TLSConfig == nil {
-		return nil, nil
-	}
+// deleteFromTarget deletes the Account JWT from a single push target.
+func (r *AccountReconciler) deleteFromTarget(ctx context.Context, operator *v1alpha1.Operator, target v1alpha1.PushTarget, operatorKP nkeys.KeyPair, publicKey string) error {
+	logger := log.FromContext(ctx).WithValues("target", target.Name)
 
-	tlsConfig := operator.Spec.TLSConfig
+	nscClient, err := r.natsClients.get(ctx, operator, target)
+	if err != nil {
+		// not sure what errors should allow finalization to skip vs fail for a retry, for now we'll only skip if the
+		// system user doesn't exist, otherwise we'll fail for a retry
+		if errors.IsNotFound(err) {
+			logger.Info("system user not found, skipping finalization")
 
-	switch {
-	case tlsConfig.CAFile != nil:
-		caFile, err := r.loadCAFile(ctx, operator.Namespace, *tlsConfig.CAFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load CA file: %w", err)
+			return nil
 		}
 
-		return []nats.Option{nsc.CABundle(caFile)}, nil
-	default:
-		return nil, fmt.Errorf("invalid TLS config: missing CA file")
-	}
-}
+		logger.Error(err, "failed to connect to account server during finalization")
 
-func (r *AccountReconciler) loadCAFile(ctx context.Context, ns string, selector v1.SecretKeySelector) ([]byte, error) {
-	secret, err := r.CoreV1.Secrets(ns).Get(ctx, selector.Name, metav1.GetOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get caFile secret: %w", err)
+		return err
 	}
 
-	key := "ca.crt"
-	if selector.Key != "" {
-		key = selector.Key
-	}
+	if err = nscClient.Delete(ctx, operatorKP, publicKey); err != nil {
+		logger.Error(err, "failed to delete account JWT")
 
-	caFile, ok := secret.Data[key]
-	if !ok {
-		return nil, fmt.Errorf("caFile secret missing key %q", key)
+		r.natsClients.invalidate(nscClient, operator, target)
+
+		return err
 	}
 
-	return caFile, nil
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *AccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.EventRecorder = mgr.GetEventRecorderFor("account-controller")
+	r.natsClients = newNATSClients(r.CoreV1, r.SysUserLoader, r.ClientPool)
 
 	logger := mgr.GetLogger().WithName("AccountReconciler")
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Account{}, indexAccountIssuer, indexAccountByIssuer); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Account{}, indexAccountAuthUser, indexAccountByAuthUser); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Account{}, indexAccountAllowedAccount, indexAccountByAllowedAccount); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Account{}, indexAccountOperatorRef, indexAccountByOperatorRef); err != nil {
+		return err
+	}
+
 	err := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Account{}).
 		Owns(&v1.Secret{}).
+		Watches(
+			&source.Kind{Type: &v1.Namespace{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+				// whenever the labels on a Namespace change, the Accounts within it need to be re-evaluated against
+				// their Operator's AccountsNamespaceSelector
+				accounts := new(v1alpha1.AccountList)
+				if err := r.List(context.Background(), accounts, client.InNamespace(obj.GetName())); err != nil {
+					logger.Error(err, "failed to list accounts in namespace", "namespace", obj.GetName())
+
+					return nil
+				}
+
+				requests := make([]reconcile.Request, len(accounts.Items))
+				for i, acc := range accounts.Items {
+					requests[i] = reconcile.Request{
+						NamespacedName: types.NamespacedName{
+							Name:      acc.Name,
+							Namespace: acc.Namespace,
+						},
+					}
+				}
+
+				return requests
+			}),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
 		Watches(
 			&source.Kind{Type: &v1alpha1.SigningKey{}},
 			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
@@ -838,24 +1531,66 @@ func (r *AccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
 					return nil
 				}
 
+				// Accounts issued by this SigningKey need to be re-issued whenever its keypair is rotated
+				accounts := new(v1alpha1.AccountList)
+				if err := r.List(context.Background(), accounts, client.MatchingFields{
+					indexAccountIssuer: issuerIndexKey("SigningKey", signingKey.Namespace, signingKey.Name),
+				}); err != nil {
+					logger.Error(err, "failed to list accounts issued by signing key", "signing_key", signingKey.Name)
+
+					return nil
+				}
+
+				requests := make([]reconcile.Request, 0, len(accounts.Items)+1)
+				for _, acc := range accounts.Items {
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{
+							Name:      acc.Name,
+							Namespace: acc.Namespace,
+						},
+					})
+				}
+
 				ownerRef := signingKey.Status.OwnerRef
 				if ownerRef == nil {
-					return nil
+					return requests
 				}
 
 				accountGVK := v1alpha1.GroupVersion.WithKind("Account")
 				if accountGVK != ownerRef.GetGroupVersionKind() {
-					return nil
+					return requests
 				}
 
-				return []reconcile.Request{{
+				return append(requests, reconcile.Request{
 					NamespacedName: types.NamespacedName{
 						Name:      ownerRef.Name,
 						Namespace: ownerRef.Namespace,
 					},
-				}}
+				})
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.User{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+				// Accounts using this User for auth callout need its public key in their JWT
+				return r.mapToAccounts(logger, indexAccountAuthUser, obj)
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.Account{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+				// Accounts allowing auth callout into this Account need its public key in their JWT
+				return r.mapToAccounts(logger, indexAccountAllowedAccount, obj)
 			}),
 		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.Operator{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+				// Accounts are pushed using the Operator's connection settings, so need to be retried when they change
+				return r.mapToAccounts(logger, indexAccountOperatorRef, obj)
+			}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Complete(r)
 
 	if err != nil {
@@ -864,3 +1599,27 @@ func (r *AccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return nil
 }
+
+// mapToAccounts enqueues the Accounts which reference obj, as recorded by the given namespaced index.
+func (r *AccountReconciler) mapToAccounts(logger logr.Logger, index string, obj client.Object) []reconcile.Request {
+	accounts := new(v1alpha1.AccountList)
+	if err := r.List(context.Background(), accounts, client.MatchingFields{
+		index: namespacedIndexKey(obj.GetNamespace(), obj.GetName()),
+	}); err != nil {
+		logger.Error(err, "failed to list accounts by index", "index", index, "name", obj.GetName(), "namespace", obj.GetNamespace())
+
+		return nil
+	}
+
+	requests := make([]reconcile.Request, len(accounts.Items))
+	for i, acc := range accounts.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      acc.Name,
+				Namespace: acc.Namespace,
+			},
+		}
+	}
+
+	return requests
+}
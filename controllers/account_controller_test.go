@@ -0,0 +1,362 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc/nsctest"
+)
+
+const tenantNamespace = "tenant"
+
+// newTestAccountReconciler returns an AccountReconciler for Accounts of operator, which is updated to push to srv,
+// holding the given objects.
+func newTestAccountReconciler(t *testing.T, operator *v1alpha1.Operator, srv *pruneServer, objects ...client.Object) *AccountReconciler {
+	t.Helper()
+
+	coreV1, natsClients := newTestPushTarget(t, operator, srv.Server)
+
+	c := newFakeClient(objects...)
+
+	return &AccountReconciler{
+		BaseReconciler: &BaseReconciler{
+			Client:        c,
+			APIReader:     c,
+			Scheme:        newTestScheme(),
+			CoreV1:        coreV1,
+			EventRecorder: record.NewFakeRecorder(100),
+		},
+		AccountsV1Alpha1: fakeAccountsClientSet{client: c},
+		natsClients:      natsClients,
+	}
+}
+
+// newTestTenantOperator returns a ready Operator which only allows Accounts in namespaces labelled with tenant=true.
+func newTestTenantOperator() *v1alpha1.Operator {
+	operator := newTestOperator()
+	operator.Spec.AccountsNamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}}
+
+	operator.Status.InitializeConditions()
+	operator.Status.MarkSeedSecretReady("OOPERATOR", "op-seed")
+
+	return operator
+}
+
+// newTestPushedAccount returns an Account in the tenant namespace issued by the Operator op, whose JWT has been pushed
+// to the default push target.
+func newTestPushedAccount() *v1alpha1.Account {
+	acc := newTestAccount("acc", "Operator", "op", "AACC")
+	acc.Namespace = tenantNamespace
+	acc.Spec.Issuer.Ref.Namespace = testNamespace
+
+	acc.Status.InitializeConditions()
+	acc.Status.MarkOperatorResolved(v1alpha1.InferredObjectReference{Namespace: testNamespace, Name: "op"})
+	acc.Status.MarkJWTSecretReady()
+	acc.Status.MarkJWTPushed()
+	acc.Status.PushTargets = []v1alpha1.PushTargetStatus{{Name: v1alpha1.DefaultPushTarget, Pushed: true}}
+
+	return acc
+}
+
+func TestResolveOperatorWithdrawsJWT(t *testing.T) {
+	ctx := context.Background()
+
+	operator := newTestTenantOperator()
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tenantNamespace, Labels: map[string]string{"tenant": "true"}}}
+
+	srv := newPruneServer(t, `[]`)
+	r := newTestAccountReconciler(t, operator, srv, ns)
+
+	acc := newTestPushedAccount()
+
+	if _, ok, err := r.resolveOperator(ctx, acc, operator); err != nil || !ok {
+		t.Fatalf("resolveOperator() = %t, %v, want the account to be allowed", ok, err)
+	}
+
+	if _, deletes := srv.requests(); deletes != 0 {
+		t.Fatalf("resolveOperator() deleted the JWT of an allowed account")
+	}
+
+	// the namespace is relabelled after the account JWT was pushed
+	ns.Labels = nil
+
+	if err := r.Update(ctx, ns); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := r.resolveOperator(ctx, acc, operator); err != nil || ok {
+		t.Fatalf("resolveOperator() = %t, %v, want the account to be rejected", ok, err)
+	}
+
+	if _, deletes := srv.requests(); deletes != 1 {
+		t.Errorf("resolveOperator() made %d delete requests, want 1", deletes)
+	}
+
+	if c := acc.Status.GetCondition(v1alpha1.AccountConditionJWTPushed); !c.IsFalse() || c.Reason != v1alpha1.ReasonNotAllowed {
+		t.Errorf("JWTPushed condition = %v, want False with reason %s", c, v1alpha1.ReasonNotAllowed)
+	}
+
+	if acc.Status.PushTargets[0].Pushed {
+		t.Errorf("push target status still reports the JWT as pushed")
+	}
+
+	// the JWT is only deleted once
+	if _, _, err := r.resolveOperator(ctx, acc, operator); err != nil {
+		t.Fatalf("resolveOperator() error = %v", err)
+	}
+
+	if _, deletes := srv.requests(); deletes != 1 {
+		t.Errorf("resolveOperator() made %d delete requests after the JWT was deleted, want 1", deletes)
+	}
+
+	// nothing is left to delete when the rejected account is deleted
+	if err := r.finalizeAccount(ctx, acc); err != nil {
+		t.Fatalf("finalizeAccount() error = %v", err)
+	}
+
+	if _, deletes := srv.requests(); deletes != 1 {
+		t.Errorf("finalizeAccount() made %d delete requests, want none", deletes-1)
+	}
+}
+
+func TestResolveOperatorStrictSigningKeyUsage(t *testing.T) {
+	ctx := context.Background()
+
+	operator := newTestOperator()
+	operator.Spec.StrictSigningKeyUsage = true
+
+	srv := newPruneServer(t, `[]`)
+	r := newTestAccountReconciler(t, operator, srv)
+
+	acc := newTestAccount("acc", "Operator", "op", "AACC")
+	acc.Status.InitializeConditions()
+
+	if _, ok, err := r.resolveOperator(ctx, acc, operator); err != nil || ok {
+		t.Fatalf("resolveOperator() = %t, %v, want the operator identity key to be rejected", ok, err)
+	}
+
+	if c := acc.Status.GetCondition(v1alpha1.AccountConditionIssuerResolved); !c.IsFalse() || c.Reason != v1alpha1.ReasonUnsupportedIssuer {
+		t.Errorf("IssuerResolved condition = %v, want False with reason %s", c, v1alpha1.ReasonUnsupportedIssuer)
+	}
+}
+
+func TestFinalizeAccountWithoutOperatorRef(t *testing.T) {
+	ctx := context.Background()
+
+	operator := newTestTenantOperator()
+	srv := newPruneServer(t, `[]`)
+
+	r := newTestAccountReconciler(t, operator, srv)
+
+	if err := r.Create(ctx, operator); err != nil {
+		t.Fatal(err)
+	}
+
+	// the operator became unresolvable after the JWT was pushed
+	acc := newTestPushedAccount()
+	acc.Status.MarkOperatorResolveUnknown(v1alpha1.ReasonNotReady, "operator is not ready")
+
+	if err := r.finalizeAccount(ctx, acc); err != nil {
+		t.Fatalf("finalizeAccount() error = %v", err)
+	}
+
+	if _, deletes := srv.requests(); deletes != 1 {
+		t.Errorf("finalizeAccount() made %d delete requests, want 1", deletes)
+	}
+}
+
+// newTestPushServer returns a push target server which accepts Account JWTs, or rejects them if fail is true, and
+// counts the pushes it receives.
+func newTestPushServer(t *testing.T, fail bool) (*nsctest.Server, *atomic.Int32) {
+	t.Helper()
+
+	srv := nsctest.NewServer(t)
+	pushes := new(atomic.Int32)
+
+	srv.Handle(nsc.RequestSubjectClaimsUpdate, func([]byte) []byte {
+		pushes.Add(1)
+
+		if fail {
+			return []byte(`{"error":{"code":500,"description":"jwt validation failed"}}`)
+		}
+
+		return []byte(`{"data":{"code":200,"message":"jwt updated"}}`)
+	})
+
+	return srv, pushes
+}
+
+func TestEnsureJWTPushed(t *testing.T) {
+	quorum := func(n int32) *int32 { return &n }
+
+	tests := []struct {
+		name         string
+		failing      []bool
+		names        []string
+		quorum       *int32
+		disabled     bool
+		wantErr      bool
+		wantStatus   v1.ConditionStatus
+		wantReason   string
+		wantPushes   int32
+		wantPushed   []bool
+		wantNoStatus bool
+	}{
+		{
+			name:       "all targets pushed",
+			failing:    []bool{false, false},
+			wantStatus: v1.ConditionTrue,
+			wantPushes: 2,
+			wantPushed: []bool{true, true},
+		},
+		{
+			name:       "below quorum",
+			failing:    []bool{false, true, true},
+			quorum:     quorum(2),
+			wantErr:    true,
+			wantStatus: v1.ConditionFalse,
+			wantReason: v1alpha1.ReasonJWTPushError,
+			wantPushes: 3,
+			wantPushed: []bool{true, false, false},
+		},
+		{
+			name:       "at quorum with a failing target",
+			failing:    []bool{false, false, true},
+			quorum:     quorum(2),
+			wantErr:    true,
+			wantStatus: v1.ConditionTrue,
+			wantPushes: 3,
+			wantPushed: []bool{true, true, false},
+		},
+		{
+			name:         "duplicate target name",
+			failing:      []bool{false, false},
+			names:        []string{"east", "east"},
+			wantStatus:   v1.ConditionFalse,
+			wantReason:   v1alpha1.ReasonInvalidSpec,
+			wantNoStatus: true,
+		},
+		{
+			name:         "push disabled",
+			failing:      []bool{false, false},
+			disabled:     true,
+			wantStatus:   v1.ConditionTrue,
+			wantNoStatus: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			operator := newTestOperator()
+			r := newTestAccountReconciler(t, operator, newPruneServer(t, `[]`))
+
+			// point the operator at the servers under test, each using the push target credentials
+			credentials := operator.Spec.PushTargets[0].CredentialsSecretRef
+
+			operator.Spec.PushTargets = nil
+			operator.Spec.PushQuorum = tt.quorum
+			operator.Spec.DisableJWTPush = tt.disabled
+
+			var pushes []*atomic.Int32
+
+			for i, fail := range tt.failing {
+				srv, counter := newTestPushServer(t, fail)
+				pushes = append(pushes, counter)
+
+				name := fmt.Sprintf("target-%d", i)
+				if tt.names != nil {
+					name = tt.names[i]
+				}
+
+				operator.Spec.PushTargets = append(operator.Spec.PushTargets, v1alpha1.PushTarget{
+					Name:                 name,
+					URL:                  srv.URL(),
+					CredentialsSecretRef: credentials,
+				})
+			}
+
+			acc, ajwt := newTestAccountJWT(t)
+
+			err := r.ensureJWTPushed(ctx, acc, operator, ajwt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ensureJWTPushed() error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			c := acc.Status.GetCondition(v1alpha1.AccountConditionJWTPushed)
+			if c.Status != tt.wantStatus || c.Reason != tt.wantReason {
+				t.Errorf("JWTPushed condition = %s %s, want %s %s", c.Status, c.Reason, tt.wantStatus, tt.wantReason)
+			}
+
+			var total int32
+			for _, counter := range pushes {
+				total += counter.Load()
+			}
+
+			if total != tt.wantPushes {
+				t.Errorf("ensureJWTPushed() pushed %d times, want %d", total, tt.wantPushes)
+			}
+
+			if tt.wantNoStatus {
+				if len(acc.Status.PushTargets) != 0 {
+					t.Errorf("push target statuses = %v, want none", acc.Status.PushTargets)
+				}
+
+				return
+			}
+
+			var pushed []bool
+			for _, status := range acc.Status.PushTargets {
+				pushed = append(pushed, status.Pushed)
+			}
+
+			if diff := cmp.Diff(tt.wantPushed, pushed); diff != "" {
+				t.Errorf("push target statuses mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// newTestAccountJWT returns an Account in the tenant namespace along with a JWT for it.
+func newTestAccountJWT(t *testing.T) (*v1alpha1.Account, string) {
+	t.Helper()
+
+	operatorKP, err := nkeys.CreateOperator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountKP, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := accountKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ajwt, err := jwt.NewAccountClaims(publicKey).Encode(operatorKP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acc := newTestAccount("acc", "Operator", "op", publicKey)
+	acc.Namespace = tenantNamespace
+	acc.Status.InitializeConditions()
+
+	return acc, ajwt
+}
@@ -10,7 +10,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -19,6 +21,9 @@ import (
 
 type BaseReconciler struct {
 	client.Client
+	// APIReader reads directly from the API server, bypassing the cache used by Client. It is used to read the latest
+	// version of an object after a conflict.
+	APIReader     client.Reader
 	Scheme        *runtime.Scheme
 	CoreV1        corev1.CoreV1Interface
 	EventRecorder record.EventRecorder
@@ -70,7 +75,7 @@ func (r *BaseReconciler) ensureSeedSecretUpToDate(ctx context.Context, owner cli
 func (r *BaseReconciler) resolveIssuer(ctx context.Context, issuer v1alpha1.IssuerReference, fallbackNamespace string) (kp v1alpha1.KeyPairable, ok bool, err error) {
 	logger := log.FromContext(ctx)
 
-	issuerGVK := issuer.Ref.GetGroupVersionKind()
+	issuerGVK := hubGroupVersionKind(issuer.Ref.APIVersion, issuer.Ref.Kind)
 
 	obj, err := r.Scheme.New(issuerGVK)
 	if err != nil {
@@ -239,3 +244,66 @@ func (r *BaseReconciler) loadIssuerSeed(ctx context.Context, issuer v1alpha1.Key
 
 	return kp, true, nil
 }
+
+// ensureAllowedBySelectors checks whether obj may be managed by a resource in ownerNamespace which restricts the objects
+// it manages with namespaceSelector and objectSelector. A nil namespaceSelector only allows objects in ownerNamespace,
+// whereas a nil objectSelector allows all objects.
+//
+// A nil error is returned if obj is allowed, otherwise the returned error should be used to mark the relevant condition.
+func (r *BaseReconciler) ensureAllowedBySelectors(ctx context.Context, obj client.Object, ownerNamespace string, namespaceSelector, objectSelector *metav1.LabelSelector) error {
+	logger := log.FromContext(ctx)
+
+	if namespaceSelector == nil {
+		if obj.GetNamespace() != ownerNamespace {
+			return ConditionFailed(v1alpha1.ReasonNotAllowed, "namespace %q is not allowed, only %q is permitted by default", obj.GetNamespace(), ownerNamespace)
+		}
+	} else {
+		nsLabelSelector, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+		if err != nil {
+			return ConditionFailed(v1alpha1.ReasonInvalidLabelSelector, "failed to parse namespace selector: %s", err.Error())
+		}
+
+		ns := new(v1.Namespace)
+		if err = r.Client.Get(ctx, client.ObjectKey{Name: obj.GetNamespace()}, ns); err != nil {
+			logger.Info("failed to get namespace", "namespace", obj.GetNamespace(), "error", err.Error())
+
+			return ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to get namespace %q: %s", obj.GetNamespace(), err.Error())
+		}
+
+		if !nsLabelSelector.Matches(labels.Set(ns.Labels)) {
+			return ConditionFailed(v1alpha1.ReasonNotAllowed, "namespace %q does not match the namespace selector", obj.GetNamespace())
+		}
+	}
+
+	// using metav1.LabelSelectorAsSelector() returns labels.Nothing() if the label selector is nil, which is not what
+	// we want, so default to labels.Everything()
+	if objectSelector == nil {
+		return nil
+	}
+
+	objLabelSelector, err := metav1.LabelSelectorAsSelector(objectSelector)
+	if err != nil {
+		return ConditionFailed(v1alpha1.ReasonInvalidLabelSelector, "failed to parse label selector: %s", err.Error())
+	}
+
+	if !objLabelSelector.Matches(labels.Set(obj.GetLabels())) {
+		return ConditionFailed(v1alpha1.ReasonNotAllowed, "%s does not match the label selector", obj.GetName())
+	}
+
+	return nil
+}
+
+// hubGroupVersionKind returns the GroupVersionKind of a referenced resource. References may use any served version of
+// the accounts.nats.io group, or omit the API version entirely, but the controllers always work with the v1alpha1 types.
+func hubGroupVersionKind(apiVersion, kind string) schema.GroupVersionKind {
+	if apiVersion == "" {
+		return v1alpha1.GroupVersion.WithKind(kind)
+	}
+
+	gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
+	if gvk.Group == v1alpha1.GroupVersion.Group {
+		gvk.Version = v1alpha1.GroupVersion.Version
+	}
+
+	return gvk
+}
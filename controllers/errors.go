@@ -3,6 +3,8 @@ package controllers
 import (
 	"errors"
 	"fmt"
+
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
 )
 
 type markConditionFunc func(reason, messageFormat string, messageA ...interface{})
@@ -52,3 +54,13 @@ func asConditionError(err error) (*conditionErr, bool) {
 
 	return nil, false
 }
+
+// isInvalidSpec returns true if err was caused by a resource spec which cannot be converted into valid JWT claims.
+func isInvalidSpec(err error) bool {
+	return errors.Is(err, nsc.ErrInvalidSpec)
+}
+
+// isRequestTimeout returns true if err was caused by a NATS server not replying to a request in time.
+func isRequestTimeout(err error) bool {
+	return errors.Is(err, nsc.ErrRequestTimeout)
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakecorev1 "k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	accountsclientsets "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned/typed/accounts/v1alpha1"
+)
+
+// newTestScheme returns a scheme with the core and accounts.nats.io types registered, as in main.go.
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	return scheme
+}
+
+// newFakeCoreV1 returns a CoreV1Interface backed by an in-memory object tracker holding objects.
+func newFakeCoreV1(objects ...runtime.Object) *fakecorev1.FakeCoreV1 {
+	tracker := clienttesting.NewObjectTracker(clientgoscheme.Scheme, clientgoscheme.Codecs.UniversalDecoder())
+
+	for _, obj := range objects {
+		utilruntime.Must(tracker.Add(obj))
+	}
+
+	fake := new(clienttesting.Fake)
+	fake.AddReactor("*", "*", clienttesting.ObjectReaction(tracker))
+
+	return &fakecorev1.FakeCoreV1{Fake: fake}
+}
+
+// newFakeClient returns a fake client holding objects which supports listing by the field indexes registered by the
+// reconcilers.
+func newFakeClient(objects ...client.Object) client.Client {
+	return &indexedClient{
+		Client: fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(objects...).Build(),
+		indexes: map[string][]client.IndexerFunc{
+			// Accounts and Users share the same index name, each indexer ignores the other type
+			indexAccountIssuer:         {indexAccountByIssuer, indexUserByIssuer},
+			indexUserAccountRef:        {indexUserByAccountRef},
+			indexAccountAuthUser:       {indexAccountByAuthUser},
+			indexAccountAllowedAccount: {indexAccountByAllowedAccount},
+			indexAccountOperatorRef:    {indexAccountByOperatorRef},
+		},
+	}
+}
+
+// indexedClient filters List results by field selectors using indexer functions, which the fake client does not
+// support.
+type indexedClient struct {
+	client.Client
+
+	indexes map[string][]client.IndexerFunc
+}
+
+func (c *indexedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := new(client.ListOptions).ApplyOptions(opts)
+
+	selector := listOpts.FieldSelector
+	if selector == nil || selector.Empty() {
+		return c.Client.List(ctx, list, opts...)
+	}
+
+	listOpts.FieldSelector = nil
+
+	if err := c.Client.List(ctx, list, listOpts); err != nil {
+		return err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	matching := make([]runtime.Object, 0, len(items))
+
+	for _, item := range items {
+		if c.matches(item.(client.Object), selector.Requirements()) {
+			matching = append(matching, item)
+		}
+	}
+
+	return meta.SetList(list, matching)
+}
+
+func (c *indexedClient) matches(obj client.Object, requirements fields.Requirements) bool {
+	for _, requirement := range requirements {
+		if !c.indexed(obj, requirement.Field, requirement.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *indexedClient) indexed(obj client.Object, field, value string) bool {
+	for _, indexer := range c.indexes[field] {
+		for _, v := range indexer(obj) {
+			if v == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// fakeAccountsClientSet implements the Account methods of the generated clientset used by the reconcilers on top of a
+// controller-runtime client, so that both see the same objects. Calling any other method panics.
+type fakeAccountsClientSet struct {
+	accountsclientsets.AccountsV1alpha1Interface
+
+	client client.Client
+}
+
+func (c fakeAccountsClientSet) Accounts(namespace string) accountsclientsets.AccountInterface {
+	return fakeAccounts{client: c.client, namespace: namespace}
+}
+
+type fakeAccounts struct {
+	accountsclientsets.AccountInterface
+
+	client    client.Client
+	namespace string
+}
+
+func (c fakeAccounts) Get(ctx context.Context, name string, _ metav1.GetOptions) (*v1alpha1.Account, error) {
+	acc := &v1alpha1.Account{}
+
+	return acc, c.client.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: name}, acc)
+}
+
+func (c fakeAccounts) UpdateStatus(ctx context.Context, acc *v1alpha1.Account, _ metav1.UpdateOptions) (*v1alpha1.Account, error) {
+	return acc, c.client.Status().Update(ctx, acc)
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// indexAccountIssuer indexes Accounts by the issuer referenced in .spec.issuer, see issuerIndexKey for the format.
+	indexAccountIssuer = ".spec.issuer"
+
+	// indexUserIssuer indexes Users by the issuer referenced in .spec.issuer, see issuerIndexKey for the format.
+	indexUserIssuer = ".spec.issuer"
+
+	// indexUserAccountRef indexes Users by the Account in .status.accountRef, formatted as "<namespace>/<name>".
+	indexUserAccountRef = ".status.accountRef"
+
+	// indexAccountAuthUser indexes Accounts by the Users in .spec.authorization.authUsers, formatted as
+	// "<namespace>/<name>".
+	indexAccountAuthUser = ".spec.authorization.authUsers"
+
+	// indexAccountAllowedAccount indexes Accounts by the Accounts in .spec.authorization.allowedAccounts, formatted as
+	// "<namespace>/<name>".
+	indexAccountAllowedAccount = ".spec.authorization.allowedAccounts"
+
+	// indexAccountOperatorRef indexes Accounts by the Operator in .status.operatorRef, formatted as
+	// "<namespace>/<name>".
+	indexAccountOperatorRef = ".status.operatorRef"
+)
+
+// issuerIndexKey returns the value used to index resources by the issuer they reference.
+func issuerIndexKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+func indexAccountByIssuer(obj client.Object) []string {
+	acc, ok := obj.(*v1alpha1.Account)
+	if !ok {
+		return nil
+	}
+
+	return []string{issuerRefIndexKey(acc.Spec.Issuer, acc.Namespace)}
+}
+
+func indexUserByIssuer(obj client.Object) []string {
+	usr, ok := obj.(*v1alpha1.User)
+	if !ok {
+		return nil
+	}
+
+	return []string{issuerRefIndexKey(usr.Spec.Issuer, usr.Namespace)}
+}
+
+// issuerRefIndexKey returns the index key for an IssuerReference, .issuer.ref.namespace is optional so defaults to the
+// namespace of the referencing resource if not set.
+func issuerRefIndexKey(issuer v1alpha1.IssuerReference, fallbackNamespace string) string {
+	ref := issuer.Ref
+
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = fallbackNamespace
+	}
+
+	return issuerIndexKey(ref.Kind, namespace, ref.Name)
+}
+
+func indexUserByAccountRef(obj client.Object) []string {
+	usr, ok := obj.(*v1alpha1.User)
+	if !ok || usr.Status.AccountRef == nil {
+		return nil
+	}
+
+	return []string{namespacedIndexKey(usr.Status.AccountRef.Namespace, usr.Status.AccountRef.Name)}
+}
+
+// namespacedIndexKey returns the value used to index resources by a namespaced object they reference.
+func namespacedIndexKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+func indexAccountByAuthUser(obj client.Object) []string {
+	acc, ok := obj.(*v1alpha1.Account)
+	if !ok || acc.Spec.Authorization == nil {
+		return nil
+	}
+
+	keys := make([]string, len(acc.Spec.Authorization.AuthUsers))
+	for i, name := range acc.Spec.Authorization.AuthUsers {
+		keys[i] = namespacedIndexKey(acc.Namespace, name)
+	}
+
+	return keys
+}
+
+func indexAccountByAllowedAccount(obj client.Object) []string {
+	acc, ok := obj.(*v1alpha1.Account)
+	if !ok || acc.Spec.Authorization == nil {
+		return nil
+	}
+
+	keys := make([]string, len(acc.Spec.Authorization.AllowedAccounts))
+	for i, ref := range acc.Spec.Authorization.AllowedAccounts {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = acc.Namespace
+		}
+
+		keys[i] = namespacedIndexKey(namespace, ref.Name)
+	}
+
+	return keys
+}
+
+func indexAccountByOperatorRef(obj client.Object) []string {
+	acc, ok := obj.(*v1alpha1.Account)
+	if !ok || acc.Status.OperatorRef == nil {
+		return nil
+	}
+
+	return []string{namespacedIndexKey(acc.Status.OperatorRef.Namespace, acc.Status.OperatorRef.Name)}
+}
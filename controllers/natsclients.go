@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+)
+
+// natsClients opens pooled connections to the push targets of an Operator, loading the TLS material and credentials
+// each target is configured with. It is shared by the controllers which need to talk to the NATS servers.
+type natsClients struct {
+	coreV1        corev1.CoreV1Interface
+	sysUserLoader *nsc.SystemUserLoader
+	pool          *nsc.ClientPool
+}
+
+func newNATSClients(coreV1 corev1.CoreV1Interface, sysUserLoader *nsc.SystemUserLoader, pool *nsc.ClientPool) *natsClients {
+	return &natsClients{
+		coreV1:        coreV1,
+		sysUserLoader: sysUserLoader,
+		pool:          pool,
+	}
+}
+
+// get returns the pooled connection for the Operator push target. The system user credentials are only
+// loaded when a new connection is required, unless the target has its own credentials.
+func (c *natsClients) get(ctx context.Context, operator *v1alpha1.Operator, target v1alpha1.PushTarget) (*nsc.Client, error) {
+	opts, material, err := c.options(ctx, operator.Namespace, target.TLSConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NATS options: %w", err)
+	}
+
+	var creds []byte
+
+	if target.CredentialsSecretRef != nil {
+		creds, err = c.loadSecretKey(ctx, operator.Namespace, *target.CredentialsSecretRef, v1alpha1.NatsSecretCredsKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load push target credentials: %w", err)
+		}
+	}
+
+	configHash := nsc.HashConfig(append(material, []byte(target.URL), creds)...)
+
+	return c.pool.Get(operator, target.Name, configHash, func(poolOpts ...nats.Option) (*nsc.Client, error) {
+		log.FromContext(ctx).Info("connecting to account server", "operator", operator.Name, "namespace", operator.Namespace, "target", target.Name)
+
+		if creds != nil {
+			return nsc.Connect(target.URL, creds, append(opts, poolOpts...)...)
+		}
+
+		sysCreds, err := c.sysUserLoader.Load(ctx, operator)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load system user credentials: %w", err)
+		}
+
+		return nsc.Connect(target.URL, sysCreds, append(opts, poolOpts...)...)
+	})
+}
+
+// invalidate drops the pooled connection for the Operator push target if it is no longer connected after a
+// failed request, so that the next attempt reconnects with freshly loaded credentials.
+func (c *natsClients) invalidate(nscClient *nsc.Client, operator *v1alpha1.Operator, target v1alpha1.PushTarget) {
+	if !nscClient.IsConnected() {
+		c.pool.Invalidate(operator.UID, target.Name)
+	}
+}
+
+// options returns the options for connecting to a NATS server with the given TLS configuration, along with the
+// TLS material used so that pooled connections are replaced when it changes. TLS is optional, and each part of the
+// configuration is only applied if it has been set.
+func (c *natsClients) options(ctx context.Context, ns string, tlsConfig *v1alpha1.TLSConfig) ([]nats.Option, [][]byte, error) {
+	if tlsConfig == nil {
+		return nil, nil, nil
+	}
+
+	var (
+		opts   []nats.Option
+		caFile []byte
+		err    error
+	)
+
+	if tlsConfig.CAFile != nil {
+		caFile, err = c.loadSecretKey(ctx, ns, *tlsConfig.CAFile, "ca.crt")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load CA file: %w", err)
+		}
+
+		opts = append(opts, nsc.CABundle(caFile))
+	}
+
+	cert, key, err := c.loadClientCertificate(ctx, ns, tlsConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cert != nil {
+		opts = append(opts, nsc.ClientCertificate(cert, key))
+	}
+
+	if tlsConfig.ServerName != "" || tlsConfig.InsecureSkipVerify {
+		opts = append(opts, nsc.TLSVerification(tlsConfig.ServerName, tlsConfig.InsecureSkipVerify))
+	}
+
+	material := [][]byte{caFile, cert, key, []byte(tlsConfig.ServerName), []byte(strconv.FormatBool(tlsConfig.InsecureSkipVerify))}
+
+	return opts, material, nil
+}
+
+// loadClientCertificate loads the client certificate and key for mutual TLS, either from a single `kubernetes.io/tls`
+// Secret or from the separate CertFile and KeyFile selectors. Both return values are nil if no client certificate is
+// configured.
+func (c *natsClients) loadClientCertificate(ctx context.Context, ns string, tlsConfig *v1alpha1.TLSConfig) (cert, key []byte, err error) {
+	switch {
+	case tlsConfig.ClientCertSecretRef != nil:
+		if tlsConfig.CertFile != nil || tlsConfig.KeyFile != nil {
+			return nil, nil, fmt.Errorf("%w: tlsConfig.clientCertSecretRef is mutually exclusive with tlsConfig.certFile and tlsConfig.keyFile", nsc.ErrInvalidSpec)
+		}
+
+		secret, err := c.coreV1.Secrets(ns).Get(ctx, tlsConfig.ClientCertSecretRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get client certificate secret: %w", err)
+		}
+
+		if cert = secret.Data[v1.TLSCertKey]; len(cert) == 0 {
+			return nil, nil, fmt.Errorf("client certificate secret missing key %q", v1.TLSCertKey)
+		}
+
+		if key = secret.Data[v1.TLSPrivateKeyKey]; len(key) == 0 {
+			return nil, nil, fmt.Errorf("client certificate secret missing key %q", v1.TLSPrivateKeyKey)
+		}
+
+		return cert, key, nil
+	case tlsConfig.CertFile != nil && tlsConfig.KeyFile != nil:
+		cert, err = c.loadSecretKey(ctx, ns, *tlsConfig.CertFile, v1.TLSCertKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load cert file: %w", err)
+		}
+
+		key, err = c.loadSecretKey(ctx, ns, *tlsConfig.KeyFile, v1.TLSPrivateKeyKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load key file: %w", err)
+		}
+
+		return cert, key, nil
+	case tlsConfig.CertFile != nil || tlsConfig.KeyFile != nil:
+		return nil, nil, fmt.Errorf("%w: tlsConfig.certFile and tlsConfig.keyFile must be set together", nsc.ErrInvalidSpec)
+	default:
+		return nil, nil, nil
+	}
+}
+
+// loadSecretKey returns the value referenced by selector, defaultKey is used if the selector does not specify a key.
+func (c *natsClients) loadSecretKey(ctx context.Context, ns string, selector v1.SecretKeySelector, defaultKey string) ([]byte, error) {
+	secret, err := c.coreV1.Secrets(ns).Get(ctx, selector.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %q: %w", selector.Name, err)
+	}
+
+	key := defaultKey
+	if selector.Key != "" {
+		key = selector.Key
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %q missing key %q", selector.Name, key)
+	}
+
+	return value, nil
+}
@@ -27,6 +27,8 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"go.uber.org/multierr"
@@ -38,6 +40,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -51,6 +55,8 @@ import (
 	"github.com/nats-io/nkeys"
 	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
 	accountsclientsets "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned/typed/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/helpers"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
 )
 
 // OperatorReconciler reconciles a Operator object
@@ -59,6 +65,15 @@ type OperatorReconciler struct {
 	Scheme            *runtime.Scheme
 	CV1Interface      corev1.CoreV1Interface
 	AccountsClientSet accountsclientsets.AccountsV1alpha1Interface
+	EventRecorder     record.EventRecorder
+	SysUserLoader     *nsc.SystemUserLoader
+	ClientPool        *nsc.ClientPool
+
+	// PruneInterval is how often the push targets of Operators with .spec.prune set are checked for unmanaged Account
+	// JWTs. Pruning is disabled for all Operators if this is zero.
+	PruneInterval time.Duration
+
+	natsClients *natsClients
 }
 
 //+kubebuilder:rbac:groups=accounts.nats.io,resources=operators,verbs=get;list;watch;create;update;patch;delete
@@ -79,6 +94,9 @@ func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 	if err := r.Get(ctx, req.NamespacedName, operator); err != nil {
 		if errors.IsNotFound(err) {
 			logger.V(1).Info("operator deleted")
+
+			r.ClientPool.InvalidateName(req.NamespacedName)
+
 			return ctrl.Result{}, nil
 		}
 
@@ -92,9 +110,14 @@ func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 	operator.Status.InitializeConditions()
 
 	defer func() {
+		operator.Status.ObserveGeneration(operator.Generation)
+
 		if !equality.Semantic.DeepEqual(originalStatus, operator.Status) {
-			if err2 := r.Status().Update(ctx, operator); err2 != nil {
-				logger.Info("failed to update operator status", "error", err2.Error())
+			base := operator.DeepCopy()
+			base.Status = *originalStatus
+
+			if err2 := patchStatus(ctx, r.Client, operator, base); err2 != nil {
+				logger.Info("failed to patch operator status", "error", err2.Error())
 
 				err = multierr.Append(err, err2)
 			}
@@ -118,7 +141,7 @@ func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{}, err
 	}
 
-	sKeys, err := r.ensureSigningKeysUpdated(ctx, operator)
+	err = r.ensureSigningKeysUpdated(ctx, operator)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.V(1).Info("signing keys not found, requeuing")
@@ -130,13 +153,32 @@ func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{}, err
 	}
 
-	if err := r.ensureJWTSecret(ctx, operator, sKeys, sysAccId); err != nil {
+	if err := r.ensureJWTSecret(ctx, operator, sysAccId); err != nil {
 		logger.Error(err, "failed to ensure JWT secret")
 
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	if err := r.ensureSystemUser(ctx, operator); err != nil {
+		logger.Error(err, "failed to ensure system user")
+
+		return ctrl.Result{}, err
+	}
+
+	if err := r.ensureServerConfig(ctx, operator, sysAccId); err != nil {
+		logger.Error(err, "failed to ensure server config")
+
+		return ctrl.Result{}, err
+	}
+
+	nextPrune, err := r.ensurePruned(ctx, operator)
+	if err != nil {
+		logger.Error(err, "failed to prune unmanaged accounts")
+
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: nextPrune}, nil
 }
 
 func (r *OperatorReconciler) ensureSeedSecret(ctx context.Context, operator *v1alpha1.Operator) error {
@@ -197,110 +239,168 @@ func (r *OperatorReconciler) ensureSeedSecret(ctx context.Context, operator *v1a
 	return nil
 }
 
-func (r *OperatorReconciler) ensureJWTSecret(ctx context.Context, operator *v1alpha1.Operator, sKeys []v1alpha1.SigningKeyEmbeddedStatus, sysAccId string) error {
+// ensureJWTSecret handles the v1alpha1.OperatorConditionJWTSecretReady condition. The Operator JWT is built from the
+// full spec and compared against the existing secret using nsc.Equality, so that any change to the spec, signing keys
+// or system account is reflected in the JWT.
+func (r *OperatorReconciler) ensureJWTSecret(ctx context.Context, operator *v1alpha1.Operator, sysAccID string) error {
 	logger := log.FromContext(ctx)
 
 	seedSecret, err := r.CV1Interface.Secrets(operator.Namespace).Get(ctx, operator.Spec.SeedSecretName, metav1.GetOptions{})
-	if errors.IsNotFound(err) {
-		logger.V(1).Info("seed secret not found, skipping jwt secret creation")
-		operator.Status.MarkJWTSecretFailed("seed secret not found", "")
-		return nil
-	}
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("seed secret not found, skipping jwt secret creation")
+
+			operator.Status.MarkJWTSecretUnknown(v1alpha1.ReasonNotFound, "seed secret not found")
+
+			return nil
+		}
+
+		operator.Status.MarkJWTSecretUnknown(v1alpha1.ReasonUnknownError, err.Error())
 
-	var sKeysPublicKeys []string
-	for _, sk := range sKeys {
-		sKeysPublicKeys = append(sKeysPublicKeys, sk.KeyPair.PublicKey)
+		return err
 	}
 
-	operatorPublicKey := string(seedSecret.Data[v1alpha1.NatsSecretPublicKeyKey])
+	kp, err := nkeys.ParseDecoratedNKey(seedSecret.Data[v1alpha1.NatsSecretSeedKey])
+	if err != nil {
+		logger.Error(err, "failed to get nkeys from seed")
 
-	jwtSec, err := r.CV1Interface.Secrets(operator.Namespace).Get(ctx, operator.Spec.JWTSecretName, metav1.GetOptions{})
-	if errors.IsNotFound(err) {
-		op := jwt.Operator{
-			SigningKeys:         sKeysPublicKeys,
-			AccountServerURL:    operator.Spec.AccountServerURL,
-			OperatorServiceURLs: operator.Spec.OperatorServiceURLs,
-			SystemAccount:       sysAccId, // This should be resolved at this point
-		}
-		opClaims := jwt.NewOperatorClaims(operator.Status.KeyPair.PublicKey)
-		opClaims.Name = operator.Name
-		opClaims.Issuer = operatorPublicKey
-		opClaims.IssuedAt = time.Now().Unix()
-		opClaims.Type = jwt.OperatorClaim
-		opClaims.Operator = op
-
-		keys, err := nkeys.ParseDecoratedNKey(seedSecret.Data[v1alpha1.NatsSecretSeedKey])
-		if err != nil {
-			logger.Error(err, "failed to get nkeys from seed")
-			return err
-		}
-		jwt, err := opClaims.Encode(keys)
-		if err != nil {
-			logger.Error(err, "failed to encode operator claims")
-			return err
-		}
+		operator.Status.MarkJWTSecretFailed(v1alpha1.ReasonInvalidSeedSecret, "failed to parse seed: %s", err.Error())
 
-		data := map[string][]byte{
-			v1alpha1.NatsSecretJWTKey: []byte(jwt),
-		}
+		return err
+	}
 
-		labels := map[string]string{
-			"operator-name": operator.Name,
+	// we want to check that any existing secret decodes to match wantClaims, if it doesn't then we will use nextJWT
+	// to create/update the secret, since the JWTs are timestamped they can never be compared directly.
+	wantClaims, nextJWT, err := nsc.CreateOperatorClaims(operator, sysAccID, kp)
+	if err != nil {
+		if isInvalidSpec(err) {
+			// retrying won't help, we need to wait for the spec to be fixed
+			operator.Status.MarkJWTSecretFailed(v1alpha1.ReasonInvalidSpec, err.Error())
+
+			return nil
 		}
 
-		jwtSecret := NewSecret(operator.Spec.JWTSecretName, operator.Namespace, WithData(data), WithLabels(labels), WithImmutable(false))
+		operator.Status.MarkJWTSecretFailed(v1alpha1.ReasonUnknownError, err.Error())
 
-		err = ctrl.SetControllerReference(operator, &jwtSecret, r.Scheme)
-		if err != nil {
-			logger.Error(err, "failed to set controller reference")
-			return err
-		}
+		return err
+	}
 
-		_, err = r.CV1Interface.Secrets(operator.Namespace).Create(ctx, &jwtSecret, metav1.CreateOptions{})
-		if err != nil {
-			operator.Status.MarkJWTSecretFailed("failed to create jwt secret for operator", "operator: %s", operator.Name)
-			logger.Error(err, "failed to create jwt secret")
-			return err
+	jwtSec, err := r.CV1Interface.Secrets(operator.Namespace).Get(ctx, operator.Spec.JWTSecretName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return r.createJWTSecret(ctx, operator, nextJWT)
 		}
 
-	} else if err != nil {
-		operator.Status.MarkJWTSecretFailed("could not find JWT secret for operator", "operator: %s", operator.Name)
 		logger.Error(err, "failed to get jwt secret")
+
+		operator.Status.MarkJWTSecretUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
 		return err
-	} else {
-		err := r.updateOperatorJWTSigningKeys(ctx, seedSecret.Data[v1alpha1.NatsSecretSeedKey], jwtSec, sKeysPublicKeys)
-		if err != nil {
-			logger.V(1).Info("failed to update operator JWT with signing keys", "error", err)
-			operator.Status.MarkJWTSecretFailed("failed to update JWT with signing keys", "")
-			return nil
-		}
 	}
 
+	return r.ensureJWTSecretUpToDate(ctx, operator, wantClaims, jwtSec, nextJWT)
+}
+
+func (r *OperatorReconciler) createJWTSecret(ctx context.Context, operator *v1alpha1.Operator, ojwt string) error {
+	logger := log.FromContext(ctx)
+
+	data := map[string][]byte{
+		v1alpha1.NatsSecretJWTKey: []byte(ojwt),
+	}
+
+	labels := map[string]string{
+		"operator-name": operator.Name,
+	}
+
+	jwtSecret := NewSecret(operator.Spec.JWTSecretName, operator.Namespace, WithData(data), WithLabels(labels), WithImmutable(false))
+
+	err := ctrl.SetControllerReference(operator, &jwtSecret, r.Scheme)
+	if err != nil {
+		logger.Error(err, "failed to set controller reference")
+
+		operator.Status.MarkJWTSecretFailed(v1alpha1.ReasonUnknownError, err.Error())
+
+		return err
+	}
+
+	_, err = r.CV1Interface.Secrets(operator.Namespace).Create(ctx, &jwtSecret, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(err, "failed to create jwt secret")
+
+		operator.Status.MarkJWTSecretFailed(v1alpha1.ReasonUnknownError, err.Error())
+
+		return err
+	}
+
+	r.EventRecorder.Eventf(operator, v1.EventTypeNormal, "JWTSecretCreated", "created secret: %s/%s", jwtSecret.Namespace, jwtSecret.Name)
+
+	operator.Status.MarkJWTSecretReady()
+
+	return nil
+}
+
+// ensureJWTSecretUpToDate compares that the existing JWT secret decodes and matches the expected claims, if it does not
+// match the secret will be updated with the nextJWT value.
+func (r *OperatorReconciler) ensureJWTSecretUpToDate(ctx context.Context, operator *v1alpha1.Operator, wantClaims *jwt.OperatorClaims, jwtSec *v1.Secret, nextJWT string) error {
+	logger := log.FromContext(ctx)
+
+	var message string
+
+	gotClaims, err := jwt.DecodeOperatorClaims(string(jwtSec.Data[v1alpha1.NatsSecretJWTKey]))
+	switch {
+	case err != nil:
+		logger.Info("failed to decode JWT from secret, updating to latest version", "reason", err.Error())
+
+		message = fmt.Sprintf("replaced invalid JWT in secret: %s/%s", jwtSec.Namespace, jwtSec.Name)
+	case !nsc.Equality.DeepEqual(gotClaims, wantClaims):
+		changes := nsc.OperatorClaimsChanges(gotClaims, wantClaims)
+
+		logger.V(1).Info("existing JWT secret does not match desired claims, updating to latest version", "changes", changes)
+
+		message = fmt.Sprintf("updated secret: %s/%s, changed: %s", jwtSec.Namespace, jwtSec.Name, strings.Join(changes, ", "))
+	default:
+		logger.V(1).Info("existing JWT secret matches desired claims, no update required")
+
+		operator.Status.MarkJWTSecretReady()
+
+		return nil
+	}
+
+	if jwtSec.Data == nil {
+		jwtSec.Data = make(map[string][]byte)
+	}
+
+	jwtSec.Data[v1alpha1.NatsSecretJWTKey] = []byte(nextJWT)
+
+	_, err = r.CV1Interface.Secrets(jwtSec.Namespace).Update(ctx, jwtSec, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(err, "failed to update jwt secret")
+
+		operator.Status.MarkJWTSecretUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+		return err
+	}
+
+	r.EventRecorder.Event(operator, v1.EventTypeNormal, "JWTSecretUpdated", message)
+
 	operator.Status.MarkJWTSecretReady()
+
 	return nil
 }
 
-func (r *OperatorReconciler) ensureSigningKeysUpdated(ctx context.Context, operator *v1alpha1.Operator) ([]v1alpha1.SigningKeyEmbeddedStatus, error) {
+func (r *OperatorReconciler) ensureSigningKeysUpdated(ctx context.Context, operator *v1alpha1.Operator) error {
 	logger := log.FromContext(ctx)
 
 	skList, err := r.AccountsClientSet.SigningKeys(operator.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil || skList == nil {
 		logger.V(1).Info("failed to list signing keys", "error:", err)
-		return nil, err
+		return err
 	}
 
-	signingKeys := make([]v1alpha1.SigningKeyEmbeddedStatus, 0)
-	for _, sk := range skList.Items {
-		if sk.Status.IsReady() && sk.Status.OwnerRef.Namespace == operator.Namespace && sk.Status.OwnerRef.Name == operator.Name {
-			signingKeys = append(signingKeys, v1alpha1.SigningKeyEmbeddedStatus{
-				Name:    sk.GetName(),
-				KeyPair: *sk.Status.KeyPair,
-			})
-		}
-	}
+	signingKeys := helpers.NextSigningKeys(operator.UID, operator.Status.SigningKeys, skList)
 
 	operator.Status.MarkSigningKeysUpdated(signingKeys)
-	return signingKeys, nil
+	return nil
 }
 
 func (r *OperatorReconciler) ensureSystemAccountResolved(ctx context.Context, operator *v1alpha1.Operator) (string, error) {
@@ -320,8 +420,6 @@ func (r *OperatorReconciler) ensureSystemAccountResolved(ctx context.Context, op
 		})
 	}
 
-	// TODO @JoeLanglands the system account should only be marked ready when it has a system user to be able to log in with!
-
 	if !sysAcc.Status.IsReady() {
 		logger.V(1).Info("system account not ready")
 		operator.Status.MarkSystemAccountNotReady("system account not ready", "")
@@ -331,56 +429,440 @@ func (r *OperatorReconciler) ensureSystemAccountResolved(ctx context.Context, op
 		}, operator.Spec.SystemAccountRef.Name)
 	}
 
-	operator.Status.MarkSystemAccountReady()
+	// the system account is only marked as ready once the system user is ready, see ensureSystemUser
 
 	return sysAcc.Status.KeyPair.PublicKey, nil
 }
 
-func (r *OperatorReconciler) updateOperatorJWTSigningKeys(ctx context.Context, operatorSeed []byte, jwtSecret *v1.Secret, sKeys []string) error {
+// systemUserName returns the name of the User managed by the controller within the Operator's system account.
+func systemUserName(operator *v1alpha1.Operator) string {
+	return operator.Name + "-system-user"
+}
+
+// ensureSystemUser creates or updates the User within the system account which the controller uses to connect to the
+// NATS servers. The system account is only marked as ready once this User is ready, so that Accounts are not pushed
+// before the credentials exist. A User with the same name which is not controlled by the Operator is never taken over,
+// since its credentials would be handed to the controller.
+func (r *OperatorReconciler) ensureSystemUser(ctx context.Context, operator *v1alpha1.Operator) error {
 	logger := log.FromContext(ctx)
 
-	operatorJWTEncoded := string(jwtSecret.Data[v1alpha1.NatsSecretJWTKey])
-	opClaims, err := jwt.DecodeOperatorClaims(operatorJWTEncoded)
+	name := systemUserName(operator)
+
+	usr := &v1alpha1.User{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: operator.Namespace,
+		},
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, usr, func() error {
+		// the resourceVersion is only set if the User already exists
+		if usr.ResourceVersion != "" && !metav1.IsControlledBy(usr, operator) {
+			return ConditionFailed(v1alpha1.ReasonNotOwned, "user %s/%s already exists and is not controlled by this operator", usr.Namespace, usr.Name)
+		}
+
+		usr.Spec.Issuer = v1alpha1.IssuerReference{
+			Ref: v1alpha1.TypedObjectReference{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       "Account",
+				Name:       operator.Spec.SystemAccountRef.Name,
+				Namespace:  operator.Namespace,
+			},
+		}
+		usr.Spec.JWTSecretName = name + v1alpha1.JWTSecretNameSuffix
+		usr.Spec.SeedSecretName = name + v1alpha1.SeedSecretNameSuffix
+		usr.Spec.CredentialsSecretName = name + v1alpha1.CredentialsSecretNameSuffix
+
+		return ctrl.SetControllerReference(operator, usr, r.Scheme)
+	})
+	if cerr, ok := asConditionError(err); ok {
+		logger.Info("system user is not controlled by the operator", "user", name)
+
+		cerr.MarkCondition(operator.Status.MarkSystemAccountNotReady, operator.Status.MarkSystemAccountUnknown)
+		r.EventRecorder.Event(operator, v1.EventTypeWarning, "SystemUserConflict", cerr.Error())
+
+		return nil
+	}
+
 	if err != nil {
-		logger.Error(err, "failed to decode operator jwt")
+		logger.Error(err, "failed to create or update system user")
+
+		operator.Status.MarkSystemAccountUnknown(v1alpha1.ReasonUnknownError, "failed to create or update system user: %s", err.Error())
+
 		return err
 	}
 
-	if isEqualUnordered(opClaims.SigningKeys, sKeys) {
-		logger.V(1).Info("operator jwt signing keys are up to date")
+	switch op {
+	case controllerutil.OperationResultCreated:
+		r.EventRecorder.Eventf(operator, v1.EventTypeNormal, "SystemUserCreated", "created user: %s/%s", usr.Namespace, usr.Name)
+	case controllerutil.OperationResultUpdated:
+		r.EventRecorder.Eventf(operator, v1.EventTypeNormal, "SystemUserUpdated", "updated user: %s/%s", usr.Namespace, usr.Name)
+	}
+
+	if !usr.Status.IsReady() {
+		logger.V(1).Info("system user not ready")
+
+		// we'll be enqueued again when the User status changes since we own it
+		operator.Status.MarkSystemAccountUnknown(v1alpha1.ReasonNotReady, "system user %s is not ready", usr.Name)
+
 		return nil
 	}
 
-	opClaims.SigningKeys = jwt.StringList(sKeys)
+	operator.Status.MarkSystemAccountReady(v1alpha1.InferredObjectReference{
+		Namespace: usr.Namespace,
+		Name:      usr.Name,
+	})
 
-	kPair, err := nkeys.ParseDecoratedNKey(operatorSeed)
-	if err != nil {
-		logger.Error(err, "failed to get nkeys from seed")
-		return err
+	return nil
+}
+
+// ensureServerConfig renders the nats-server configuration for the Operator into the ConfigMap named by
+// .spec.serverConfig. The memory resolver preloads the JWTs of all Accounts which resolve to the Operator, so the
+// Operator is also reconciled whenever an Account JWT Secret changes.
+func (r *OperatorReconciler) ensureServerConfig(ctx context.Context, operator *v1alpha1.Operator, sysAccID string) error {
+	logger := log.FromContext(ctx)
+
+	config := operator.Spec.ServerConfig
+	if config == nil {
+		return nil
 	}
-	ojwt, err := opClaims.Encode(kPair)
+
+	jwtSecret, err := r.CV1Interface.Secrets(operator.Namespace).Get(ctx, operator.Spec.JWTSecretName, metav1.GetOptions{})
 	if err != nil {
-		logger.Error(err, "failed to encode operator jwt")
-		return err
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("jwt secret not found, skipping server config")
+
+			return nil
+		}
+
+		return fmt.Errorf("failed to get operator jwt secret: %w", err)
 	}
 
-	jwtSecret.Data[v1alpha1.NatsSecretJWTKey] = []byte(ojwt)
-	_, err = r.CV1Interface.Secrets(jwtSecret.Namespace).Update(ctx, jwtSecret, metav1.UpdateOptions{})
+	var accounts map[string]string
+
+	if config.ResolverType == v1alpha1.ResolverTypeMemory {
+		accounts, err = r.loadAccountJWTs(ctx, operator)
+		if err != nil {
+			return err
+		}
+	}
+
+	rendered := nsc.RenderServerConfig(config, string(jwtSecret.Data[v1alpha1.NatsSecretJWTKey]), sysAccID, accounts)
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.ConfigMapName,
+			Namespace: operator.Namespace,
+		},
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		cm.Data = map[string]string{
+			nsc.ServerConfigKey: rendered,
+		}
+
+		return ctrl.SetControllerReference(operator, cm, r.Scheme)
+	})
 	if err != nil {
-		logger.Error(err, "failed to update jwt secret")
-		return err
+		return fmt.Errorf("failed to create or update server config: %w", err)
+	}
+
+	switch op {
+	case controllerutil.OperationResultCreated:
+		r.EventRecorder.Eventf(operator, v1.EventTypeNormal, "ServerConfigCreated", "created config map: %s/%s", cm.Namespace, cm.Name)
+	case controllerutil.OperationResultUpdated:
+		r.EventRecorder.Eventf(operator, v1.EventTypeNormal, "ServerConfigUpdated", "updated config map: %s/%s", cm.Namespace, cm.Name)
 	}
 
 	return nil
 }
 
+// loadAccountJWTs returns the JWT of each Account which resolves to the Operator, keyed by public key. Accounts whose
+// JWT Secret is not yet ready are skipped, the Operator is reconciled again once it is.
+func (r *OperatorReconciler) loadAccountJWTs(ctx context.Context, operator *v1alpha1.Operator) (map[string]string, error) {
+	accounts := new(v1alpha1.AccountList)
+
+	err := r.List(ctx, accounts, client.MatchingFields{
+		indexAccountOperatorRef: namespacedIndexKey(operator.Namespace, operator.Name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	jwts := make(map[string]string, len(accounts.Items))
+
+	for _, acc := range accounts.Items {
+		if acc.Status.KeyPair == nil || !acc.Status.GetCondition(v1alpha1.AccountConditionJWTSecretReady).IsTrue() {
+			continue
+		}
+
+		secret := new(v1.Secret)
+
+		err := r.Get(ctx, types.NamespacedName{Namespace: acc.Namespace, Name: acc.Spec.JWTSecretName}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to get account jwt secret: %w", err)
+		}
+
+		jwts[acc.Status.KeyPair.PublicKey] = string(secret.Data[v1alpha1.NatsSecretJWTKey])
+	}
+
+	return jwts, nil
+}
+
+// mapAccountSecretToOperator enqueues the Operator of the Account which controls the Secret, so that the rendered
+// server config is updated when an Account JWT changes.
+func (r *OperatorReconciler) mapAccountSecretToOperator(obj client.Object) []reconcile.Request {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil || owner.Kind != "Account" || owner.APIVersion != v1alpha1.GroupVersion.String() {
+		return nil
+	}
+
+	acc := new(v1alpha1.Account)
+	if err := r.Get(context.Background(), types.NamespacedName{Namespace: obj.GetNamespace(), Name: owner.Name}, acc); err != nil {
+		return nil
+	}
+
+	operatorRef := acc.Status.OperatorRef
+	if operatorRef == nil {
+		return nil
+	}
+
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Name:      operatorRef.Name,
+			Namespace: operatorRef.Namespace,
+		},
+	}}
+}
+
+// ensurePruned deletes Account JWTs from the Operator's push targets which do not belong to any Account managed by the
+// Operator, when enabled by .spec.prune. An unmanaged Account JWT is only deleted if it was also found by the previous
+// run, so that an Account which has been pushed but not yet had its status updated is never pruned. Pruning is skipped
+// while the public key of any Account issued by the Operator is unknown. It returns the duration until the next run is
+// due.
+func (r *OperatorReconciler) ensurePruned(ctx context.Context, operator *v1alpha1.Operator) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+
+	if operator.Spec.Prune == nil || r.PruneInterval <= 0 {
+		operator.Status.Prune = nil
+
+		return 0, nil
+	}
+
+	if next := r.nextPrune(operator); next > 0 {
+		return next, nil
+	}
+
+	if !operator.Status.IsReady() {
+		logger.V(1).Info("operator not ready, skipping pruning")
+
+		return 0, nil
+	}
+
+	managed, pending, err := r.managedAccounts(ctx, operator)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(pending) > 0 {
+		logger.Info("accounts issued by the operator do not have a keypair, skipping pruning", "accounts", pending)
+
+		return r.PruneInterval, nil
+	}
+
+	var operatorKP nkeys.KeyPair
+
+	if !operator.Spec.Prune.DryRun {
+		operatorKP, err = r.loadOperatorKeyPair(ctx, operator)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	previous := make(map[v1alpha1.UnmanagedAccount]bool)
+
+	// JWTs found by a dry run are only deleted once found again by a run which is not, so that disabling dry runs
+	// does not immediately delete everything they reported
+	if operator.Status.Prune != nil && !operator.Status.Prune.DryRun {
+		for _, unmanaged := range operator.Status.Prune.UnmanagedAccounts {
+			unmanaged.Deleted = false
+
+			previous[unmanaged] = true
+		}
+	}
+
+	now := metav1.Now()
+	status := &v1alpha1.AccountPruningStatus{LastPruneTime: &now, DryRun: operator.Spec.Prune.DryRun}
+
+	var errs error
+
+	for _, target := range helpers.PushTargets(operator) {
+		nscClient, err := r.natsClients.get(ctx, operator, target)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("%s: %w", target.Name, err))
+
+			continue
+		}
+
+		accounts, err := nscClient.List(ctx)
+		if err != nil {
+			r.natsClients.invalidate(nscClient, operator, target)
+
+			errs = multierr.Append(errs, fmt.Errorf("%s: %w", target.Name, err))
+
+			continue
+		}
+
+		for _, publicKey := range accounts {
+			if managed[publicKey] {
+				continue
+			}
+
+			unmanaged := v1alpha1.UnmanagedAccount{Target: target.Name, PublicKey: publicKey}
+
+			if operator.Spec.Prune.DryRun || !previous[unmanaged] {
+				r.EventRecorder.Eventf(operator, v1.EventTypeNormal, "UnmanagedAccountFound", "found unmanaged account %s on push target %s", publicKey, target.Name)
+
+				status.UnmanagedAccounts = append(status.UnmanagedAccounts, unmanaged)
+
+				continue
+			}
+
+			if err := nscClient.Delete(ctx, operatorKP, publicKey); err != nil {
+				r.natsClients.invalidate(nscClient, operator, target)
+
+				r.EventRecorder.Eventf(operator, v1.EventTypeWarning, "AccountPruneFailed", "failed to delete unmanaged account %s from push target %s: %s", publicKey, target.Name, err.Error())
+
+				errs = multierr.Append(errs, fmt.Errorf("%s: %w", target.Name, err))
+
+				status.UnmanagedAccounts = append(status.UnmanagedAccounts, unmanaged)
+
+				continue
+			}
+
+			r.EventRecorder.Eventf(operator, v1.EventTypeNormal, "AccountPruned", "deleted unmanaged account %s from push target %s", publicKey, target.Name)
+
+			unmanaged.Deleted = true
+
+			status.UnmanagedAccounts = append(status.UnmanagedAccounts, unmanaged)
+		}
+	}
+
+	operator.Status.Prune = status
+
+	return r.PruneInterval, errs
+}
+
+// nextPrune returns the duration until pruning is next due for the Operator, or zero if it is due now.
+func (r *OperatorReconciler) nextPrune(operator *v1alpha1.Operator) time.Duration {
+	if operator.Status.Prune == nil || operator.Status.Prune.LastPruneTime == nil {
+		return 0
+	}
+
+	remaining := time.Until(operator.Status.Prune.LastPruneTime.Add(r.PruneInterval))
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
+
+// managedAccounts returns the public keys of all Accounts issued by the Operator, either directly or by one of its
+// SigningKeys, along with any accounts excluded from pruning. Accounts are matched by their .spec.issuer as well as
+// .status.operatorRef, since the latter is cleared while an Account's issuer cannot be resolved and such an Account
+// must never be treated as unmanaged. pending lists the Accounts whose public key is not currently known, their JWTs
+// cannot be told apart from unmanaged ones. The indexes are registered by the AccountReconciler.
+func (r *OperatorReconciler) managedAccounts(ctx context.Context, operator *v1alpha1.Operator) (managed map[string]bool, pending []string, err error) {
+	matchingFields := []client.MatchingFields{
+		{indexAccountIssuer: issuerIndexKey("Operator", operator.Namespace, operator.Name)},
+		{indexAccountOperatorRef: namespacedIndexKey(operator.Namespace, operator.Name)},
+	}
+
+	signingKeys := new(v1alpha1.SigningKeyList)
+	if err := r.List(ctx, signingKeys, client.InNamespace(operator.Namespace)); err != nil {
+		return nil, nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+
+	for _, sk := range signingKeys.Items {
+		// the spec is used rather than .status.ownerRef, which is cleared while the owner cannot be resolved
+		if sk.Spec.OwnerRef.Kind != v1alpha1.SigningKeyTypeOperator || sk.Spec.OwnerRef.Name != operator.Name {
+			continue
+		}
+
+		matchingFields = append(matchingFields, client.MatchingFields{
+			indexAccountIssuer: issuerIndexKey("SigningKey", sk.Namespace, sk.Name),
+		})
+	}
+
+	managed = make(map[string]bool, len(operator.Spec.Prune.ExcludedAccounts))
+	seen := make(map[types.UID]bool)
+
+	for _, fields := range matchingFields {
+		accounts := new(v1alpha1.AccountList)
+		if err := r.List(ctx, accounts, fields); err != nil {
+			return nil, nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+
+		for _, acc := range accounts.Items {
+			if seen[acc.UID] {
+				continue
+			}
+
+			seen[acc.UID] = true
+
+			if acc.Status.KeyPair == nil {
+				pending = append(pending, namespacedIndexKey(acc.Namespace, acc.Name))
+
+				continue
+			}
+
+			managed[acc.Status.KeyPair.PublicKey] = true
+		}
+	}
+
+	for _, publicKey := range operator.Spec.Prune.ExcludedAccounts {
+		managed[publicKey] = true
+	}
+
+	return managed, pending, nil
+}
+
+// loadOperatorKeyPair loads the Operator's key pair from its seed secret, which is required to sign requests to delete
+// Account JWTs.
+func (r *OperatorReconciler) loadOperatorKeyPair(ctx context.Context, operator *v1alpha1.Operator) (nkeys.KeyPair, error) {
+	seedSecret, err := r.CV1Interface.Secrets(operator.Namespace).Get(ctx, operator.Spec.SeedSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to load operator seed: %w", err)
+	}
+
+	kp, err := nkeys.ParseDecoratedNKey(seedSecret.Data[v1alpha1.NatsSecretSeedKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse operator seed data: %w", err)
+	}
+
+	return kp, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *OperatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.EventRecorder = mgr.GetEventRecorderFor("operator-controller")
+	r.natsClients = newNATSClients(r.CV1Interface, r.SysUserLoader, r.ClientPool)
+
 	logger := mgr.GetLogger().WithName("OperatorReconciler")
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Operator{}).
 		Owns(&v1.Secret{}).
+		Owns(&v1alpha1.User{}).
+		Owns(&v1.ConfigMap{}).
+		Watches(
+			&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapAccountSecretToOperator),
+		).
 		Watches(
 			&source.Kind{Type: &v1alpha1.Account{}},
 			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
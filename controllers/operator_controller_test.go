@@ -0,0 +1,417 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	fakecorev1 "k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc/nsctest"
+)
+
+func newTestOperator() *v1alpha1.Operator {
+	return &v1alpha1.Operator{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "op",
+			Namespace: testNamespace,
+			UID:       "op-uid",
+		},
+		Spec: v1alpha1.OperatorSpec{
+			SeedSecretName: "op-seed",
+			Prune:          &v1alpha1.AccountPruning{},
+		},
+		Status: v1alpha1.OperatorStatus{
+			Status: v1alpha1.Status{
+				Conditions: apis.Conditions{{Type: apis.ConditionReady, Status: v1.ConditionTrue}},
+			},
+		},
+	}
+}
+
+// newTestAccount returns an Account issued by the resource of the given kind and name, with publicKey as its keypair
+// unless it is empty.
+func newTestAccount(name, issuerKind, issuerName, publicKey string) *v1alpha1.Account {
+	acc := &v1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			UID:       types.UID(name + "-uid"),
+		},
+		Spec: v1alpha1.AccountSpec{
+			Issuer: v1alpha1.IssuerReference{
+				Ref: v1alpha1.TypedObjectReference{Kind: issuerKind, Name: issuerName},
+			},
+		},
+	}
+
+	if publicKey != "" {
+		acc.Status.KeyPair = &v1alpha1.KeyPair{PublicKey: publicKey}
+	}
+
+	return acc
+}
+
+func newTestOperatorSigningKey(name, operator string) *v1alpha1.SigningKey {
+	return &v1alpha1.SigningKey{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: v1alpha1.SigningKeySpec{
+			Type: v1alpha1.SigningKeyTypeOperator,
+			OwnerRef: v1alpha1.SigningKeyOwnerReference{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       v1alpha1.SigningKeyTypeOperator,
+				Name:       operator,
+			},
+		},
+	}
+}
+
+func TestManagedAccounts(t *testing.T) {
+	operator := newTestOperator()
+	operator.Spec.Prune.ExcludedAccounts = []string{"AEXCLUDED"}
+
+	// an Account whose issuer cannot currently be resolved has its operatorRef cleared
+	unresolved := newTestAccount("unresolved", "Operator", "op", "AUNRESOLVED")
+
+	resolved := newTestAccount("resolved", "Operator", "op", "ARESOLVED")
+	resolved.Status.OperatorRef = &v1alpha1.InferredObjectReference{Namespace: testNamespace, Name: "op"}
+
+	other := newTestAccount("other", "SigningKey", "other-sk", "AOTHER")
+	other.Status.OperatorRef = &v1alpha1.InferredObjectReference{Namespace: testNamespace, Name: "other"}
+
+	r := &OperatorReconciler{
+		Client: newFakeClient(
+			newTestOperatorSigningKey("op-sk", "op"),
+			newTestOperatorSigningKey("other-sk", "other"),
+			unresolved,
+			resolved,
+			newTestAccount("signed", "SigningKey", "op-sk", "ASIGNED"),
+			newTestAccount("pending", "SigningKey", "op-sk", ""),
+			other,
+		),
+	}
+
+	managed, pending, err := r.managedAccounts(context.Background(), operator)
+	if err != nil {
+		t.Fatalf("managedAccounts() error = %v", err)
+	}
+
+	wantManaged := map[string]bool{
+		"AUNRESOLVED": true,
+		"ARESOLVED":   true,
+		"ASIGNED":     true,
+		"AEXCLUDED":   true,
+	}
+
+	if diff := cmp.Diff(wantManaged, managed); diff != "" {
+		t.Errorf("managedAccounts() managed mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"nats/pending"}, pending); diff != "" {
+		t.Errorf("managedAccounts() pending mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEnsureSystemUser(t *testing.T) {
+	operator := newTestOperator()
+	operator.Spec.SystemAccountRef = v1.LocalObjectReference{Name: "sys"}
+
+	controlled := &v1alpha1.User{ObjectMeta: metav1.ObjectMeta{Name: "op-system-user", Namespace: testNamespace}}
+	if err := ctrl.SetControllerReference(operator, controlled, newTestScheme()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		existing   []client.Object
+		wantStatus v1.ConditionStatus
+		wantReason string
+		wantIssuer string
+	}{
+		{
+			name:       "created",
+			wantStatus: v1.ConditionUnknown,
+			wantReason: v1alpha1.ReasonNotReady,
+			wantIssuer: "sys",
+		},
+		{
+			name:       "controlled by the operator",
+			existing:   []client.Object{controlled.DeepCopy()},
+			wantStatus: v1.ConditionUnknown,
+			wantReason: v1alpha1.ReasonNotReady,
+			wantIssuer: "sys",
+		},
+		{
+			name: "not controlled by the operator",
+			existing: []client.Object{&v1alpha1.User{
+				ObjectMeta: metav1.ObjectMeta{Name: "op-system-user", Namespace: testNamespace},
+				Spec:       v1alpha1.UserSpec{Issuer: v1alpha1.IssuerReference{Ref: v1alpha1.TypedObjectReference{Kind: "Account", Name: "other"}}},
+			}},
+			wantStatus: v1.ConditionFalse,
+			wantReason: v1alpha1.ReasonNotOwned,
+			wantIssuer: "other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			r := &OperatorReconciler{
+				Client:        newFakeClient(tt.existing...),
+				Scheme:        newTestScheme(),
+				EventRecorder: record.NewFakeRecorder(10),
+			}
+
+			operator := operator.DeepCopy()
+			operator.Status.InitializeConditions()
+
+			if err := r.ensureSystemUser(ctx, operator); err != nil {
+				t.Fatalf("ensureSystemUser() error = %v", err)
+			}
+
+			c := operator.Status.GetCondition(v1alpha1.OperatorConditionSystemAccountReady)
+			if c.Status != tt.wantStatus || c.Reason != tt.wantReason {
+				t.Errorf("SystemAccountReady condition = %s %s, want %s %s", c.Status, c.Reason, tt.wantStatus, tt.wantReason)
+			}
+
+			usr := &v1alpha1.User{}
+			if err := r.Get(ctx, client.ObjectKey{Namespace: testNamespace, Name: "op-system-user"}, usr); err != nil {
+				t.Fatal(err)
+			}
+
+			if usr.Spec.Issuer.Ref.Name != tt.wantIssuer {
+				t.Errorf("system user issuer = %s, want %s", usr.Spec.Issuer.Ref.Name, tt.wantIssuer)
+			}
+		})
+	}
+}
+
+// pruneServer is a push target which lists the given accounts and records delete requests.
+type pruneServer struct {
+	*nsctest.Server
+
+	mu      sync.Mutex
+	lists   int
+	deletes int
+}
+
+func newPruneServer(t *testing.T, accounts string) *pruneServer {
+	srv := &pruneServer{Server: nsctest.NewServer(t)}
+
+	srv.Handle(nsc.RequestSubjectClaimsList, func([]byte) []byte {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+
+		srv.lists++
+
+		return []byte(`{"data":` + accounts + `}`)
+	})
+
+	srv.Handle(nsc.RequestSubjectClaimsDelete, func([]byte) []byte {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+
+		srv.deletes++
+
+		return []byte(`{"data":{"code":200,"message":"deleted"}}`)
+	})
+
+	return srv
+}
+
+func (s *pruneServer) requests() (lists, deletes int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lists, s.deletes
+}
+
+// newTestPruneReconciler returns an OperatorReconciler for operator, which is updated to push to srv, holding the
+// given objects.
+func newTestPruneReconciler(t *testing.T, operator *v1alpha1.Operator, srv *pruneServer, objects ...client.Object) *OperatorReconciler {
+	t.Helper()
+
+	coreV1, natsClients := newTestPushTarget(t, operator, srv.Server)
+
+	return &OperatorReconciler{
+		Client:        newFakeClient(objects...),
+		CV1Interface:  coreV1,
+		EventRecorder: record.NewFakeRecorder(100),
+		PruneInterval: time.Hour,
+		natsClients:   natsClients,
+	}
+}
+
+// newTestPushTarget updates operator to push to srv with a new identity keypair, and returns a CoreV1Interface holding
+// the Operator seed and push target credentials along with the NATS clients used to connect to srv.
+func newTestPushTarget(t *testing.T, operator *v1alpha1.Operator, srv *nsctest.Server) (*fakecorev1.FakeCoreV1, *natsClients) {
+	t.Helper()
+
+	operatorKP, err := nkeys.CreateOperator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	operatorSeed, err := operatorKP.Seed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	creds := newTestCreds(t)
+
+	operator.Spec.PushTargets = []v1alpha1.PushTarget{
+		{
+			Name: v1alpha1.DefaultPushTarget,
+			URL:  srv.URL(),
+			CredentialsSecretRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "push-creds"},
+			},
+		},
+	}
+
+	seedSecret := NewSecret("op-seed", testNamespace, WithData(map[string][]byte{v1alpha1.NatsSecretSeedKey: operatorSeed}))
+	credsSecret := NewSecret("push-creds", testNamespace, WithData(map[string][]byte{v1alpha1.NatsSecretCredsKey: creds}))
+
+	coreV1 := newFakeCoreV1(&seedSecret, &credsSecret)
+
+	pool := nsc.NewClientPool()
+	t.Cleanup(pool.Close)
+
+	return coreV1, newNATSClients(coreV1, nil, pool)
+}
+
+// newTestCreds returns the credentials of a user, the fake NATS server does not check them.
+func newTestCreds(t *testing.T) []byte {
+	t.Helper()
+
+	accountKP, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userKP, err := nkeys.CreateUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userPublicKey, err := userKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userJWT, err := jwt.NewUserClaims(userPublicKey).Encode(accountKP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userSeed, err := userKP.Seed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err := jwt.FormatUserConfig(userJWT, userSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return creds
+}
+
+func TestEnsurePruned(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dry run findings are confirmed before deleting", func(t *testing.T) {
+		operator := newTestOperator()
+		operator.Spec.Prune.DryRun = true
+
+		srv := newPruneServer(t, `["AMANAGED","AUNMANAGED"]`)
+		r := newTestPruneReconciler(t, operator, srv, newTestAccount("managed", "Operator", "op", "AMANAGED"))
+
+		wantUnmanaged := []v1alpha1.UnmanagedAccount{{Target: v1alpha1.DefaultPushTarget, PublicKey: "AUNMANAGED"}}
+
+		prune := func(wantDryRun bool) {
+			t.Helper()
+
+			// make the next run due
+			if operator.Status.Prune != nil {
+				operator.Status.Prune.LastPruneTime = nil
+			}
+
+			if _, err := r.ensurePruned(ctx, operator); err != nil {
+				t.Fatalf("ensurePruned() error = %v", err)
+			}
+
+			if operator.Status.Prune.DryRun != wantDryRun {
+				t.Errorf("Prune.DryRun = %t, want %t", operator.Status.Prune.DryRun, wantDryRun)
+			}
+		}
+
+		prune(true)
+		prune(true)
+
+		if diff := cmp.Diff(wantUnmanaged, operator.Status.Prune.UnmanagedAccounts); diff != "" {
+			t.Errorf("dry run UnmanagedAccounts mismatch (-want +got):\n%s", diff)
+		}
+
+		operator.Spec.Prune.DryRun = false
+
+		prune(false)
+
+		if _, deletes := srv.requests(); deletes != 0 {
+			t.Fatalf("first run after disabling dry run deleted %d accounts, want none", deletes)
+		}
+
+		if diff := cmp.Diff(wantUnmanaged, operator.Status.Prune.UnmanagedAccounts); diff != "" {
+			t.Errorf("UnmanagedAccounts mismatch (-want +got):\n%s", diff)
+		}
+
+		prune(false)
+
+		if _, deletes := srv.requests(); deletes != 1 {
+			t.Errorf("second run deleted %d accounts, want 1", deletes)
+		}
+
+		wantUnmanaged[0].Deleted = true
+
+		if diff := cmp.Diff(wantUnmanaged, operator.Status.Prune.UnmanagedAccounts); diff != "" {
+			t.Errorf("UnmanagedAccounts mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("skipped while an account has no keypair", func(t *testing.T) {
+		operator := newTestOperator()
+		operator.Status.Prune = &v1alpha1.AccountPruningStatus{
+			UnmanagedAccounts: []v1alpha1.UnmanagedAccount{{Target: v1alpha1.DefaultPushTarget, PublicKey: "APENDING"}},
+		}
+
+		srv := newPruneServer(t, `["APENDING"]`)
+		r := newTestPruneReconciler(t, operator, srv, newTestAccount("pending", "Operator", "op", ""))
+
+		requeueAfter, err := r.ensurePruned(ctx, operator)
+		if err != nil {
+			t.Fatalf("ensurePruned() error = %v", err)
+		}
+
+		if requeueAfter != r.PruneInterval {
+			t.Errorf("ensurePruned() requeueAfter = %s, want %s", requeueAfter, r.PruneInterval)
+		}
+
+		if lists, deletes := srv.requests(); lists != 0 || deletes != 0 {
+			t.Errorf("ensurePruned() made %d list and %d delete requests, want none", lists, deletes)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// patchStatus writes the status of obj with a merge patch against base, which should be a copy of obj holding the
+// status as it was read at the start of the reconcile. Only the status fields changed by the reconcile are sent, and
+// the patch does not include the resourceVersion, so it is never rejected with a conflict when the object has been
+// modified since it was read from the cache. Lists such as the conditions are replaced as a whole, which is safe since
+// the status of Operators, Users and SigningKeys is only written by their own controller, which never reconciles the
+// same object concurrently, and every condition is recomputed by each reconcile. The patch is skipped if the object has
+// since been deleted.
+func patchStatus(ctx context.Context, c client.Client, obj, base client.Object) error {
+	return client.IgnoreNotFound(c.Status().Patch(ctx, obj, client.MergeFrom(base)))
+}
+
+// patchAccountStatus writes the status of acc like patchStatus, except that the patch is conditional on the
+// resourceVersion. The User controller adds revocations to the Account status, and since a merge patch replaces the
+// whole list an unconditional patch would drop any revocation added after acc was read. On conflict the revocations are
+// read from the latest Account and pruned again, and the rest of the status computed by the reconcile is applied on top.
+// The latest Account is read with reader, which should bypass the cache since it may not have caught up with the write
+// that caused the conflict.
+func patchAccountStatus(ctx context.Context, c client.Client, reader client.Reader, acc, base *v1alpha1.Account) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := c.Status().Patch(ctx, acc, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+		if !errors.IsConflict(err) {
+			return err
+		}
+
+		latest := &v1alpha1.Account{}
+		if getErr := reader.Get(ctx, client.ObjectKeyFromObject(acc), latest); getErr != nil {
+			return getErr
+		}
+
+		status := acc.Status.DeepCopy()
+		status.Revocations = latest.Status.Revocations
+
+		base = latest.DeepCopy()
+		latest.Status = *status
+
+		pruneRevocations(latest)
+
+		*acc = *latest
+
+		return err
+	})
+
+	return client.IgnoreNotFound(err)
+}
+
+// addFinalizer adds finalizer to obj with a patch, see patchFinalizers.
+func addFinalizer(ctx context.Context, c client.Client, reader client.Reader, obj client.Object, finalizer string) error {
+	return patchFinalizers(ctx, c, reader, obj, func(o client.Object) bool {
+		return controllerutil.AddFinalizer(o, finalizer)
+	})
+}
+
+// removeFinalizer removes finalizer from obj with a patch, see patchFinalizers.
+func removeFinalizer(ctx context.Context, c client.Client, reader client.Reader, obj client.Object, finalizer string) error {
+	return patchFinalizers(ctx, c, reader, obj, func(o client.Object) bool {
+		return controllerutil.RemoveFinalizer(o, finalizer)
+	})
+}
+
+// patchFinalizers calls mutate to add or remove a finalizer and patches the finalizers of obj. Merge patches replace
+// the whole list, so the patch is conditional on the resourceVersion to avoid dropping finalizers changed by others.
+// On conflict the latest finalizers are read with reader, which should bypass the cache, and mutate is called again. Only the finalizers and
+// resourceVersion of obj are updated, so that any status changes already made by the reconcile are kept.
+func patchFinalizers(ctx context.Context, c client.Client, reader client.Reader, obj client.Object, mutate func(client.Object) bool) error {
+	latest, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("expected a client.Object but got %T", obj)
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		base := latest.DeepCopyObject().(client.Object)
+
+		if mutate(latest) {
+			err := c.Patch(ctx, latest, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+			if errors.IsConflict(err) {
+				if getErr := reader.Get(ctx, client.ObjectKeyFromObject(latest), latest); getErr != nil {
+					return getErr
+				}
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		obj.SetResourceVersion(latest.GetResourceVersion())
+		obj.SetFinalizers(latest.GetFinalizers())
+
+		return nil
+	})
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+func revocation(publicKey string, expiresAt *metav1.Time) v1alpha1.UserRevocation {
+	return v1alpha1.UserRevocation{
+		PublicKey: publicKey,
+		RevokedAt: metav1.NewTime(time.Now().Add(-2 * time.Hour).Truncate(time.Second)),
+		ExpiresAt: expiresAt,
+		UserRef:   v1alpha1.InferredObjectReference{Namespace: testNamespace, Name: publicKey},
+	}
+}
+
+// staleCache is a client whose reads return a copy of stale, like a cache which has not caught up with later writes.
+type staleCache struct {
+	client.Client
+	stale client.Object
+}
+
+func (c staleCache) Get(_ context.Context, _ client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	return c.Scheme().Convert(c.stale.DeepCopyObject(), obj, nil)
+}
+
+// TestPatchAccountStatusConcurrentRevoke checks that a User revoked while the Account is being reconciled is not
+// dropped when the Account reconcile prunes expired revocations and patches its status.
+func TestPatchAccountStatusConcurrentRevoke(t *testing.T) {
+	ctx := context.Background()
+
+	expired := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+
+	acc := newTestAccount("acc", "Operator", "op", "AACC")
+	acc.Status.Revocations = []v1alpha1.UserRevocation{
+		revocation("UEXPIRED", &expired),
+		revocation("UKEPT", nil),
+	}
+
+	c := newFakeClient(acc)
+
+	// the Account reconcile reads the Account and prunes its revocations
+	reconciled := &v1alpha1.Account{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(acc), reconciled); err != nil {
+		t.Fatal(err)
+	}
+
+	base := reconciled.DeepCopy()
+
+	pruneRevocations(reconciled)
+	reconciled.Status.MarkJWTPushed()
+
+	// meanwhile a User of the Account is deleted and its key revoked
+	usr := &v1alpha1.User{ObjectMeta: metav1.ObjectMeta{Name: "usr", Namespace: testNamespace}}
+
+	users := &UserReconciler{
+		BaseReconciler:    &BaseReconciler{Client: c, CoreV1: newFakeCoreV1()},
+		AccountsClientSet: fakeAccountsClientSet{client: c},
+		EventRecorder:     record.NewFakeRecorder(10),
+	}
+
+	ref := v1alpha1.InferredObjectReference{Namespace: testNamespace, Name: acc.Name}
+	if err := users.revokeUserKey(ctx, usr, ref, "UREVOKED"); err != nil {
+		t.Fatalf("revokeUserKey() error = %v", err)
+	}
+
+	// the cache has not seen the revocation, so the conflict must be resolved by reading from the API server
+	cache := staleCache{Client: c, stale: base.DeepCopy()}
+
+	if err := patchAccountStatus(ctx, cache, c, reconciled, base); err != nil {
+		t.Fatalf("patchAccountStatus() error = %v", err)
+	}
+
+	got := &v1alpha1.Account{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(acc), got); err != nil {
+		t.Fatal(err)
+	}
+
+	var revoked []string
+	for _, r := range got.Status.Revocations {
+		revoked = append(revoked, r.PublicKey)
+	}
+
+	if diff := cmp.Diff([]string{"UKEPT", "UREVOKED"}, revoked); diff != "" {
+		t.Errorf("revocations mismatch (-want +got):\n%s", diff)
+	}
+
+	if c := got.Status.GetCondition(v1alpha1.AccountConditionJWTPushed); !c.IsTrue() {
+		t.Errorf("JWTPushed condition = %v, want the status of the reconcile to be kept", c)
+	}
+
+	if diff := cmp.Diff(got.Status, reconciled.Status); diff != "" {
+		t.Errorf("reconciled Account does not match the patched status (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,49 @@
+package resources
+
+import (
+	"github.com/nats-io/nkeys"
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// XKeySecretBuilder builds the Secret holding the curve keypair used to encrypt auth callout requests for an Account.
+type XKeySecretBuilder struct {
+	scheme *runtime.Scheme
+	secret *v1.Secret
+}
+
+func NewXKeySecretBuilder(scheme *runtime.Scheme) *XKeySecretBuilder {
+	return &XKeySecretBuilder{
+		scheme: scheme,
+		secret: &v1.Secret{},
+	}
+}
+
+func NewXKeySecretBuilderFromSecret(s *v1.Secret, scheme *runtime.Scheme) *XKeySecretBuilder {
+	return &XKeySecretBuilder{
+		scheme: scheme,
+		secret: s,
+	}
+}
+
+func (b *XKeySecretBuilder) Build(acc *v1alpha1.Account, kp nkeys.KeyPair) (*v1.Secret, error) {
+	seed, err := kp.Seed()
+	if err != nil {
+		return nil, err
+	}
+
+	pubkey, err := kp.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	b.secret.Name = acc.Spec.Authorization.XKeySecretName
+	b.secret.Namespace = acc.Namespace
+	b.secret.Data = map[string][]byte{
+		v1alpha1.NatsSecretSeedKey:      seed,
+		v1alpha1.NatsSecretPublicKeyKey: []byte(pubkey),
+	}
+
+	return b.secret, nil
+}
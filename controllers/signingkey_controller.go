@@ -31,7 +31,6 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"go.uber.org/multierr"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -88,9 +87,14 @@ func (r *SigningKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	signingKey.Status.InitializeConditions()
 
 	defer func() {
+		signingKey.Status.ObserveGeneration(signingKey.Generation)
+
 		if !equality.Semantic.DeepEqual(originalStatus, signingKey.Status) {
-			if err2 := r.Status().Update(ctx, signingKey); err2 != nil {
-				logger.Error(err2, "failed to update signing key status")
+			base := signingKey.DeepCopy()
+			base.Status = *originalStatus
+
+			if err2 := patchStatus(ctx, r.Client, signingKey, base); err2 != nil {
+				logger.Error(err2, "failed to patch signing key status")
 
 				err = multierr.Append(err, err2)
 			}
@@ -107,85 +111,238 @@ func (r *SigningKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		logger.Error(err, "failed to ensure key pair")
 		return ctrl.Result{}, err
 	}
-	return ctrl.Result{}, nil
+
+	requeueAfter, err := r.ensureRotated(ctx, signingKey)
+	if err != nil {
+		logger.Error(err, "failed to rotate key pair")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *SigningKeyReconciler) ensureKeyPair(ctx context.Context, signingKey *v1alpha1.SigningKey) error {
 	logger := log.FromContext(ctx)
 
+	// once rotated, the current keypair is no longer stored in the secret named in the spec, so prefer the secret
+	// referenced by the status if we have one.
+	secretName := signingKey.Spec.SeedSecretName
+	if signingKey.Status.KeyPair != nil {
+		secretName = signingKey.Status.KeyPair.SeedSecretName
+	}
+
 	var publicKey string
-	secret, err := r.CV1Interface.Secrets(signingKey.Namespace).Get(ctx, signingKey.Spec.SeedSecretName, metav1.GetOptions{})
+	secret, err := r.CV1Interface.Secrets(signingKey.Namespace).Get(ctx, secretName, metav1.GetOptions{})
 	if errors.IsNotFound(err) {
-		var keyPair nkeys.KeyPair
-		switch signingKey.Spec.OwnerRef.Kind {
-		case v1alpha1.SigningKeyTypeAccount:
-			keyPair, err = nkeys.CreateAccount()
-		case v1alpha1.SigningKeyTypeOperator:
-			keyPair, err = nkeys.CreateOperator()
-		default:
-			err := errors.NewBadRequest(fmt.Sprintf("unknown owner kind: %s", signingKey.Spec.OwnerRef.Kind))
-			return err
-		}
+		publicKey, err = r.createSeedSecret(ctx, signingKey, secretName)
 		if err != nil {
-			logger.Error(err, "failed to create key pair")
 			return err
 		}
+	} else if err != nil {
+		logger.Error(err, "failed to fetch seed secret")
+		return err
+	} else {
+		publicKey = string(secret.Data[v1alpha1.NatsSecretPublicKeyKey])
+	}
 
-		seed, err := keyPair.Seed()
-		if err != nil {
-			logger.Error(err, "failed to get seed")
-			return err
-		}
-		publicKey, err = keyPair.PublicKey()
-		if err != nil {
-			logger.Error(err, "failed to get public key")
-			return err
-		}
+	signingKey.Status.MarkSeedSecretReady(publicKey, secretName)
 
-		data := map[string][]byte{
-			v1alpha1.NatsSecretSeedKey:      seed,
-			v1alpha1.NatsSecretPublicKeyKey: []byte(publicKey),
-		}
+	return nil
+}
 
-		labels := map[string]string{
-			"operator-name": signingKey.Spec.OwnerRef.Name,
-			"secret-type":   string(v1alpha1.NatsSecretTypeSKey),
-		}
+// createSeedSecret generates a new keypair for the SigningKey and stores it in a secret with the given name, returning
+// the public key of the new keypair.
+func (r *SigningKeyReconciler) createSeedSecret(ctx context.Context, signingKey *v1alpha1.SigningKey, name string) (string, error) {
+	logger := log.FromContext(ctx)
+
+	var keyPair nkeys.KeyPair
+	var err error
+	switch signingKey.Spec.OwnerRef.Kind {
+	case v1alpha1.SigningKeyTypeAccount:
+		keyPair, err = nkeys.CreateAccount()
+	case v1alpha1.SigningKeyTypeOperator:
+		keyPair, err = nkeys.CreateOperator()
+	default:
+		err := errors.NewBadRequest(fmt.Sprintf("unknown owner kind: %s", signingKey.Spec.OwnerRef.Kind))
+		return "", err
+	}
+	if err != nil {
+		logger.Error(err, "failed to create key pair")
+		return "", err
+	}
+
+	seed, err := keyPair.Seed()
+	if err != nil {
+		logger.Error(err, "failed to get seed")
+		return "", err
+	}
+	publicKey, err := keyPair.PublicKey()
+	if err != nil {
+		logger.Error(err, "failed to get public key")
+		return "", err
+	}
 
-		secret := NewSecret(signingKey.Spec.SeedSecretName, signingKey.Namespace, WithImmutable(true), WithLabels(labels), WithData(data))
+	data := map[string][]byte{
+		v1alpha1.NatsSecretSeedKey:      seed,
+		v1alpha1.NatsSecretPublicKeyKey: []byte(publicKey),
+	}
 
-		if err = ctrl.SetControllerReference(signingKey, &secret, r.Scheme); err != nil {
-			logger.Error(err, "failed to set controller reference")
-			return err
+	labels := map[string]string{
+		"operator-name": signingKey.Spec.OwnerRef.Name,
+		"secret-type":   string(v1alpha1.NatsSecretTypeSKey),
+	}
+
+	secret := NewSecret(name, signingKey.Namespace, WithImmutable(true), WithLabels(labels), WithData(data))
+
+	if err = ctrl.SetControllerReference(signingKey, &secret, r.Scheme); err != nil {
+		logger.Error(err, "failed to set controller reference")
+		return "", err
+	}
+
+	_, err = r.CV1Interface.Secrets(signingKey.Namespace).Create(ctx, &secret, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(err, "failed to create seed secret")
+		return "", err
+	}
+
+	return publicKey, nil
+}
+
+// ensureRotated retires any previous keypairs whose grace period has passed, and rotates the current keypair if the
+// rotation interval has elapsed. The returned duration is the time until the next retirement or rotation is due, or
+// zero if no further action is scheduled.
+func (r *SigningKeyReconciler) ensureRotated(ctx context.Context, signingKey *v1alpha1.SigningKey) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+
+	now := metav1.Now()
+
+	if err := r.retireKeyPairs(ctx, signingKey, now); err != nil {
+		return 0, err
+	}
+
+	policy := signingKey.Spec.Rotation
+	if policy == nil || policy.Interval.Duration <= 0 || signingKey.Status.KeyPair == nil {
+		return nextRetirement(signingKey, now), nil
+	}
+
+	lastRotation := signingKey.CreationTimestamp
+	if signingKey.Status.LastRotationTime != nil {
+		lastRotation = *signingKey.Status.LastRotationTime
+	}
+
+	dueAt := lastRotation.Add(policy.Interval.Duration)
+
+	if due := dueAt.Sub(now.Time); due > 0 {
+		return minPositiveDuration(due, nextRetirement(signingKey, now)), nil
+	}
+
+	// the secret is named after the time the rotation became due rather than the current time, so that a reconcile
+	// which reads a stale status before this rotation has been recorded reuses the same keypair instead of rotating
+	// again and orphaning this one.
+	secretName := fmt.Sprintf("%s-%d", signingKey.Spec.SeedSecretName, dueAt.Unix())
+
+	publicKey, err := r.createSeedSecret(ctx, signingKey, secretName)
+	if errors.IsAlreadyExists(err) {
+		publicKey, err = r.rotatedPublicKey(ctx, signingKey, secretName)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Info("rotated signing key", "previous_public_key", signingKey.Status.KeyPair.PublicKey, "public_key", publicKey)
+
+	signingKey.Status.MarkKeyPairRotated(v1alpha1.KeyPair{
+		PublicKey:      publicKey,
+		SeedSecretName: secretName,
+	}, now, metav1.NewTime(now.Add(policy.GracePeriod.Duration)))
+
+	return minPositiveDuration(policy.Interval.Duration, nextRetirement(signingKey, now)), nil
+}
+
+// rotatedPublicKey returns the public key stored in a seed secret created by a previous attempt at the current
+// rotation. The secret must be controlled by the SigningKey, so that a secret created by someone else is never trusted.
+func (r *SigningKeyReconciler) rotatedPublicKey(ctx context.Context, signingKey *v1alpha1.SigningKey, name string) (string, error) {
+	secret, err := r.CV1Interface.Secrets(signingKey.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to fetch rotated seed secret", "secret", name)
+
+		return "", err
+	}
+
+	if !metav1.IsControlledBy(secret, signingKey) {
+		return "", fmt.Errorf("seed secret %s/%s already exists and is not controlled by this signing key", signingKey.Namespace, name)
+	}
+
+	return string(secret.Data[v1alpha1.NatsSecretPublicKeyKey]), nil
+}
+
+// retireKeyPairs deletes the seed secrets of any retiring keypairs which have passed their grace period and removes
+// them from the status, the owner will drop them from its JWT on its next reconcile.
+func (r *SigningKeyReconciler) retireKeyPairs(ctx context.Context, signingKey *v1alpha1.SigningKey, now metav1.Time) error {
+	logger := log.FromContext(ctx)
+
+	for _, kp := range signingKey.Status.RetiringKeyPairs {
+		if kp.RetireAt.After(now.Time) {
+			continue
 		}
 
-		_, err = r.CV1Interface.Secrets(signingKey.Namespace).Create(ctx, &secret, metav1.CreateOptions{})
-		if err != nil {
-			logger.Error(err, "failed to create seed secret")
+		err := r.CV1Interface.Secrets(signingKey.Namespace).Delete(ctx, kp.SeedSecretName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "failed to delete retired seed secret", "secret", kp.SeedSecretName)
+
 			return err
 		}
-	} else if err != nil {
-		logger.Error(err, "failed to fetch seed secret")
-		return err
-	} else {
-		publicKey = string(secret.Data[v1alpha1.NatsSecretPublicKeyKey])
-	}
 
-	signingKey.Status.MarkSeedSecretReady(publicKey, signingKey.Spec.SeedSecretName)
+		logger.Info("retired signing key", "public_key", kp.PublicKey)
+
+		signingKey.Status.MarkKeyPairRetired(kp.PublicKey, now)
+	}
 
 	return nil
 }
 
+// nextRetirement returns the time until the next retiring keypair should be retired, or zero if there are none.
+func nextRetirement(signingKey *v1alpha1.SigningKey, now metav1.Time) time.Duration {
+	var next time.Duration
+
+	for _, kp := range signingKey.Status.RetiringKeyPairs {
+		next = minPositiveDuration(next, kp.RetireAt.Sub(now.Time))
+	}
+
+	return next
+}
+
+// minPositiveDuration returns the smallest of a and b which is greater than zero, or zero if neither are.
+func minPositiveDuration(a, b time.Duration) time.Duration {
+	switch {
+	case a <= 0 && b <= 0:
+		return 0
+	case a <= 0:
+		return b
+	case b <= 0, a < b:
+		return a
+	default:
+		return b
+	}
+}
+
 func (r *SigningKeyReconciler) ensureOwnerResolved(ctx context.Context, signingKey *v1alpha1.SigningKey) error {
 	logger := log.FromContext(ctx)
 
 	ownerRef := signingKey.Spec.OwnerRef
-	ownerGVK := schema.FromAPIVersionAndKind(ownerRef.APIVersion, ownerRef.Kind)
+	ownerGVK := hubGroupVersionKind(ownerRef.APIVersion, ownerRef.Kind)
 
 	ownerRuntimeObj, _ := r.Scheme.New(ownerGVK)
 	switch ownerRuntimeObj.(type) {
-	case *v1alpha1.Account, *v1alpha1.Operator:
+	case *v1alpha1.Account:
 		break
+	case *v1alpha1.Operator:
+		if signingKey.Spec.Scope != nil {
+			signingKey.Status.MarkOwnerResolveFailed(v1alpha1.ReasonUnsupportedScope, "scoped signing keys are only supported for Accounts")
+
+			return nil
+		}
 	default:
 		signingKey.Status.MarkOwnerResolveFailed("UnsupportedOwnerKind", "owner must be one of Account or Operator")
 
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+const testNamespace = "nats"
+
+// newTestSigningKey returns an Account SigningKey whose current keypair was generated at lastRotation and which is
+// rotated every hour.
+func newTestSigningKey(lastRotation time.Time) *v1alpha1.SigningKey {
+	return &v1alpha1.SigningKey{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "sk",
+			Namespace:         testNamespace,
+			UID:               "sk-uid",
+			CreationTimestamp: metav1.NewTime(lastRotation),
+		},
+		Spec: v1alpha1.SigningKeySpec{
+			Type:           v1alpha1.SigningKeyTypeAccount,
+			SeedSecretName: "sk-seed",
+			OwnerRef: v1alpha1.SigningKeyOwnerReference{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       v1alpha1.SigningKeyTypeAccount,
+				Name:       "acc",
+			},
+			Rotation: &v1alpha1.SigningKeyRotationPolicy{
+				Interval:    metav1.Duration{Duration: time.Hour},
+				GracePeriod: metav1.Duration{Duration: 10 * time.Minute},
+			},
+		},
+		Status: v1alpha1.SigningKeyStatus{
+			KeyPair: &v1alpha1.KeyPair{
+				PublicKey:      "APREVIOUS",
+				SeedSecretName: "sk-seed",
+			},
+		},
+	}
+}
+
+func newTestSigningKeyReconciler(secrets ...runtime.Object) *SigningKeyReconciler {
+	return &SigningKeyReconciler{
+		Scheme:       newTestScheme(),
+		CV1Interface: newFakeCoreV1(secrets...),
+	}
+}
+
+func listSecretNames(t *testing.T, r *SigningKeyReconciler) []string {
+	t.Helper()
+
+	secrets, err := r.CV1Interface.Secrets(testNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list secrets: %v", err)
+	}
+
+	names := make([]string, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		names = append(names, secret.Name)
+	}
+
+	return names
+}
+
+func TestEnsureRotated(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not configured", func(t *testing.T) {
+		sk := newTestSigningKey(time.Now().Add(-2 * time.Hour))
+		sk.Spec.Rotation = nil
+
+		r := newTestSigningKeyReconciler()
+
+		requeueAfter, err := r.ensureRotated(ctx, sk)
+		if err != nil {
+			t.Fatalf("ensureRotated() error = %v", err)
+		}
+
+		if requeueAfter != 0 {
+			t.Errorf("ensureRotated() requeueAfter = %s, want 0", requeueAfter)
+		}
+
+		if sk.Status.KeyPair.PublicKey != "APREVIOUS" {
+			t.Errorf("keypair was rotated without a rotation policy")
+		}
+	})
+
+	t.Run("not due", func(t *testing.T) {
+		sk := newTestSigningKey(time.Now().Add(-30 * time.Minute))
+
+		r := newTestSigningKeyReconciler()
+
+		requeueAfter, err := r.ensureRotated(ctx, sk)
+		if err != nil {
+			t.Fatalf("ensureRotated() error = %v", err)
+		}
+
+		if requeueAfter <= 29*time.Minute || requeueAfter > 30*time.Minute {
+			t.Errorf("ensureRotated() requeueAfter = %s, want ~30m", requeueAfter)
+		}
+
+		if sk.Status.KeyPair.PublicKey != "APREVIOUS" {
+			t.Errorf("keypair was rotated before the interval elapsed")
+		}
+
+		if names := listSecretNames(t, r); len(names) != 0 {
+			t.Errorf("secrets = %v, want none", names)
+		}
+	})
+
+	t.Run("due", func(t *testing.T) {
+		lastRotation := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		sk := newTestSigningKey(lastRotation)
+
+		r := newTestSigningKeyReconciler()
+
+		requeueAfter, err := r.ensureRotated(ctx, sk)
+		if err != nil {
+			t.Fatalf("ensureRotated() error = %v", err)
+		}
+
+		if requeueAfter != 10*time.Minute {
+			t.Errorf("ensureRotated() requeueAfter = %s, want the grace period", requeueAfter)
+		}
+
+		wantSecret := fmt.Sprintf("sk-seed-%d", lastRotation.Add(time.Hour).Unix())
+
+		if got := sk.Status.KeyPair.SeedSecretName; got != wantSecret {
+			t.Errorf("KeyPair.SeedSecretName = %s, want %s", got, wantSecret)
+		}
+
+		if sk.Status.KeyPair.PublicKey == "APREVIOUS" || sk.Status.KeyPair.PublicKey == "" {
+			t.Errorf("KeyPair.PublicKey = %q, want a new public key", sk.Status.KeyPair.PublicKey)
+		}
+
+		if len(sk.Status.RetiringKeyPairs) != 1 || sk.Status.RetiringKeyPairs[0].PublicKey != "APREVIOUS" {
+			t.Errorf("RetiringKeyPairs = %v, want the previous keypair", sk.Status.RetiringKeyPairs)
+		}
+
+		if sk.Status.LastRotationTime == nil {
+			t.Errorf("LastRotationTime was not set")
+		}
+	})
+
+	t.Run("stale status", func(t *testing.T) {
+		sk := newTestSigningKey(time.Now().Add(-2 * time.Hour))
+
+		// the second reconcile reads the SigningKey from the cache before the first rotation has been recorded
+		stale := sk.DeepCopy()
+
+		r := newTestSigningKeyReconciler()
+
+		if _, err := r.ensureRotated(ctx, sk); err != nil {
+			t.Fatalf("ensureRotated() error = %v", err)
+		}
+
+		if _, err := r.ensureRotated(ctx, stale); err != nil {
+			t.Fatalf("ensureRotated() with a stale status error = %v", err)
+		}
+
+		if *stale.Status.KeyPair != *sk.Status.KeyPair {
+			t.Errorf("stale reconcile rotated to %v, want %v", *stale.Status.KeyPair, *sk.Status.KeyPair)
+		}
+
+		if names := listSecretNames(t, r); len(names) != 1 {
+			t.Errorf("secrets = %v, want a single rotated secret", names)
+		}
+	})
+
+	t.Run("secret not controlled", func(t *testing.T) {
+		lastRotation := time.Now().Add(-2 * time.Hour)
+		sk := newTestSigningKey(lastRotation)
+
+		existing := NewSecret(fmt.Sprintf("sk-seed-%d", lastRotation.Add(time.Hour).Unix()), testNamespace, WithData(map[string][]byte{
+			v1alpha1.NatsSecretPublicKeyKey: []byte("AUNTRUSTED"),
+		}))
+
+		r := newTestSigningKeyReconciler(&existing)
+
+		if _, err := r.ensureRotated(ctx, sk); err == nil {
+			t.Fatalf("ensureRotated() error = nil, want an error")
+		}
+
+		if sk.Status.KeyPair.PublicKey != "APREVIOUS" {
+			t.Errorf("KeyPair.PublicKey = %s, want the keypair to be unchanged", sk.Status.KeyPair.PublicKey)
+		}
+	})
+}
+
+func TestRetireKeyPairs(t *testing.T) {
+	ctx := context.Background()
+	now := metav1.Now()
+
+	sk := newTestSigningKey(now.Add(-2 * time.Hour))
+	sk.Status.RetiringKeyPairs = []v1alpha1.RetiringKeyPair{
+		{
+			KeyPair:  v1alpha1.KeyPair{PublicKey: "AEXPIRED", SeedSecretName: "sk-seed-1"},
+			RetireAt: metav1.NewTime(now.Add(-time.Minute)),
+		},
+		{
+			KeyPair:  v1alpha1.KeyPair{PublicKey: "AMISSING", SeedSecretName: "sk-seed-2"},
+			RetireAt: metav1.NewTime(now.Add(-time.Minute)),
+		},
+		{
+			KeyPair:  v1alpha1.KeyPair{PublicKey: "ARETIRING", SeedSecretName: "sk-seed-3"},
+			RetireAt: metav1.NewTime(now.Add(time.Minute)),
+		},
+	}
+	sk.Status.RotationHistory = []v1alpha1.SigningKeyRotationRecord{
+		{PreviousPublicKey: "AEXPIRED", PublicKey: "AMISSING"},
+	}
+
+	expired := NewSecret("sk-seed-1", testNamespace)
+	retiring := NewSecret("sk-seed-3", testNamespace)
+
+	r := newTestSigningKeyReconciler(&expired, &retiring)
+
+	if err := r.retireKeyPairs(ctx, sk, now); err != nil {
+		t.Fatalf("retireKeyPairs() error = %v", err)
+	}
+
+	if len(sk.Status.RetiringKeyPairs) != 1 || sk.Status.RetiringKeyPairs[0].PublicKey != "ARETIRING" {
+		t.Errorf("RetiringKeyPairs = %v, want only ARETIRING", sk.Status.RetiringKeyPairs)
+	}
+
+	if retiredAt := sk.Status.RotationHistory[0].RetiredAt; retiredAt == nil || !retiredAt.Equal(&now) {
+		t.Errorf("RotationHistory[0].RetiredAt = %v, want %v", retiredAt, now)
+	}
+
+	_, err := r.CV1Interface.Secrets(testNamespace).Get(ctx, "sk-seed-1", metav1.GetOptions{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expired seed secret was not deleted, error = %v", err)
+	}
+
+	if _, err := r.CV1Interface.Secrets(testNamespace).Get(ctx, "sk-seed-3", metav1.GetOptions{}); err != nil {
+		t.Errorf("retiring seed secret was deleted, error = %v", err)
+	}
+
+	if got := nextRetirement(sk, now); got != time.Minute {
+		t.Errorf("nextRetirement() = %s, want 1m", got)
+	}
+}
+
+func TestMinPositiveDuration(t *testing.T) {
+	tests := []struct {
+		a, b time.Duration
+		want time.Duration
+	}{
+		{a: 0, b: 0, want: 0},
+		{a: -time.Second, b: -time.Minute, want: 0},
+		{a: 0, b: time.Second, want: time.Second},
+		{a: time.Second, b: 0, want: time.Second},
+		{a: -time.Second, b: time.Minute, want: time.Minute},
+		{a: time.Minute, b: -time.Second, want: time.Minute},
+		{a: time.Second, b: time.Minute, want: time.Second},
+		{a: time.Minute, b: time.Second, want: time.Second},
+		{a: time.Minute, b: time.Minute, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s,%s", tt.a, tt.b), func(t *testing.T) {
+			if got := minPositiveDuration(tt.a, tt.b); got != tt.want {
+				t.Errorf("minPositiveDuration(%s, %s) = %s, want %s", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -28,16 +28,28 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
 	"github.com/versori-oss/nats-account-operator/controllers/resources"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 
 	"go.uber.org/multierr"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/nats-io/jwt/v2"
 	"github.com/nats-io/nkeys"
@@ -46,11 +58,15 @@ import (
 	"github.com/versori-oss/nats-account-operator/pkg/nsc"
 )
 
+const UserFinalizer = "accounts.nats.io/finalizer"
+
 // UserReconciler reconciles a User object
 type UserReconciler struct {
 	*BaseReconciler
 	AccountsClientSet accountsclientsets.AccountsV1alpha1Interface
 	EventRecorder     record.EventRecorder
+
+	logger logr.Logger
 }
 
 //+kubebuilder:rbac:groups=accounts.nats.io,resources=users,verbs=get;list;watch;create;update;patch;delete
@@ -80,20 +96,58 @@ func (r *UserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 	usr.Status.InitializeConditions()
 
 	defer func() {
+		usr.Status.ObserveGeneration(usr.Generation)
+
 		if !equality.Semantic.DeepEqual(originalStatus, usr.Status) {
-			if err2 := r.Status().Update(ctx, usr); err2 != nil {
-				logger.Info("failed to update user status", "error", err2.Error())
+			base := usr.DeepCopy()
+			base.Status = *originalStatus
+
+			if err2 := patchStatus(ctx, r.Client, usr, base); err2 != nil {
+				logger.Info("failed to patch user status", "error", err2.Error())
 
 				err = multierr.Append(err, err2)
 			}
 		}
 	}()
 
+	if usr.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(usr, UserFinalizer) {
+			if err := addFinalizer(ctx, r.Client, r.APIReader, usr, UserFinalizer); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else {
+		if controllerutil.ContainsFinalizer(usr, UserFinalizer) {
+			if err := r.finalizeUser(ctx, usr); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			logger.V(1).Info("user successfully finalized")
+
+			if err := removeFinalizer(ctx, r.Client, r.APIReader, usr, UserFinalizer); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, nil
+	}
+
 	seed, ok, err := r.reconcileSeedSecret(ctx, usr)
 	if err != nil || !ok {
 		return ctrl.Result{}, err
 	}
 
+	// if the keypair has been replaced then JWTs issued for the previous key must be revoked by the Account which
+	// issued them.
+	if prev := originalStatus.KeyPair; prev != nil && issuedAccountRef(originalStatus) != nil && prev.PublicKey != usr.Status.KeyPair.PublicKey {
+		if err := r.revokeUserKey(ctx, usr, *issuedAccountRef(originalStatus), prev.PublicKey); err != nil {
+			// keep the previous key in the status so the revocation is retried on the next reconcile
+			usr.Status.KeyPair = originalStatus.KeyPair
+
+			return ctrl.Result{}, err
+		}
+	}
+
 	// get the KeyPairable which will be used to sign the JWT, resolveIssuer is part of BaseReconciler which doesn't
 	// mark conditions (since it doesn't know what resource type it's reconciling), so we need to check for condition
 	// errors and mark the conditions accordingly
@@ -112,16 +166,26 @@ func (r *UserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 		return ctrl.Result{}, err
 	}
 
-	_, ok, err = r.resolveAccount(ctx, usr, keyPairable)
+	account, ok, err := r.resolveAccount(ctx, usr, keyPairable)
 	if err != nil || !ok {
 		logger.Error(err, "failed to ensure owner resolved")
 
 		return ctrl.Result{}, err
 	}
 
+	// similarly, if the User has moved to a different Account then the previous Account must revoke the key.
+	if prev := issuedAccountRef(originalStatus); prev != nil && originalStatus.KeyPair != nil && *prev != *usr.Status.AccountRef {
+		if err := r.revokeUserKey(ctx, usr, *prev, originalStatus.KeyPair.PublicKey); err != nil {
+			usr.Status.AccountRef = originalStatus.AccountRef
+			usr.Status.IssuedAccountRef = originalStatus.IssuedAccountRef
+
+			return ctrl.Result{}, err
+		}
+	}
+
 	logger.V(1).Info("reconciling user JWT secret")
 
-	ujwt, ok, err := r.reconcileJWTSecret(ctx, usr, keyPairable)
+	ujwt, ok, err := r.reconcileJWTSecret(ctx, usr, keyPairable, account)
 	if err != nil || !ok {
 		logger.Error(err, "failed to reconcile user jwt secret")
 
@@ -132,6 +196,10 @@ func (r *UserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 		return ctrl.Result{}, err
 	}
 
+	renewAfter := markJWTClaims(usr, ujwt)
+
+	usr.Status.IssuedAccountRef = usr.Status.AccountRef.DeepCopy()
+
 	logger.V(1).Info("reconciling user credential secret")
 
 	if err := r.reconcileUserCredentialSecret(ctx, usr, ujwt, seed); err != nil {
@@ -140,7 +208,7 @@ func (r *UserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: renewAfter}, nil
 }
 
 // reconcileSeedSecret handles the v1alpha1.KeyPairableConditionSeedSecretReady condition. It ensures that a secret
@@ -240,6 +308,10 @@ func (r *UserReconciler) resolveAccount(ctx context.Context, acc *v1alpha1.User,
 	case *v1alpha1.Account:
 		logger.V(1).Info("user issuer is an account")
 
+		if ok, err := r.ensureIdentityKeyAllowed(ctx, acc, v); err != nil || !ok {
+			return nil, false, err
+		}
+
 		account = v
 	case *v1alpha1.SigningKey:
 		logger.V(1).Info("user issuer is a signing key, resolving owner")
@@ -253,7 +325,7 @@ func (r *UserReconciler) resolveAccount(ctx context.Context, acc *v1alpha1.User,
 			}
 
 			if ok {
-				return nil, true, nil
+				return nil, false, nil
 			}
 
 			return nil, false, err
@@ -272,6 +344,30 @@ func (r *UserReconciler) resolveAccount(ctx context.Context, acc *v1alpha1.User,
 		return nil, false, nil
 	}
 
+	err = r.ensureAllowedBySelectors(ctx, acc, account.Namespace, account.Spec.UsersNamespaceSelector, account.Spec.UsersSelector)
+	if err != nil {
+		if cerr, ok := asConditionError(err); ok {
+			// marking the condition clears .status.accountRef, so keep hold of the Account which issued the credentials
+			issued := issuedAccountRef(&acc.Status)
+
+			cerr.MarkCondition(acc.Status.MarkAccountResolveFailed, acc.Status.MarkAccountResolveUnknown)
+
+			if cerr.failure {
+				r.EventRecorder.Eventf(acc, v1.EventTypeWarning, "AccountRejected", "user rejected by account %s/%s: %s", account.Namespace, account.Name, err.Error())
+
+				if cerr.reason == v1alpha1.ReasonNotAllowed {
+					return nil, false, r.revokeIssuedKey(ctx, acc, issued)
+				}
+
+				return nil, false, nil
+			}
+		} else {
+			acc.Status.MarkAccountResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+		}
+
+		return nil, false, err
+	}
+
 	acc.Status.MarkAccountResolved(v1alpha1.InferredObjectReference{
 		Namespace: account.Namespace,
 		Name:      account.Name,
@@ -280,7 +376,40 @@ func (r *UserReconciler) resolveAccount(ctx context.Context, acc *v1alpha1.User,
 	return account, true, nil
 }
 
-func (r *UserReconciler) reconcileJWTSecret(ctx context.Context, usr *v1alpha1.User, keyPairable v1alpha1.KeyPairable) (string, bool, error) {
+// ensureIdentityKeyAllowed checks that the Operator of account allows Users to be issued by the Account identity key,
+// which it does not if it requires strict signing key usage since the server would reject the User.
+func (r *UserReconciler) ensureIdentityKeyAllowed(ctx context.Context, usr *v1alpha1.User, account *v1alpha1.Account) (bool, error) {
+	ref := account.Status.OperatorRef
+	if ref == nil {
+		usr.Status.MarkIssuerResolveUnknown(v1alpha1.ReasonNotReady, "account %s has not resolved its operator", account.Name)
+
+		return false, nil
+	}
+
+	operator := new(v1alpha1.Operator)
+
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, operator); err != nil {
+		if errors.IsNotFound(err) {
+			usr.Status.MarkIssuerResolveUnknown(v1alpha1.ReasonNotFound, "operator %s/%s not found", ref.Namespace, ref.Name)
+
+			return false, nil
+		}
+
+		usr.Status.MarkIssuerResolveUnknown(v1alpha1.ReasonUnknownError, err.Error())
+
+		return false, err
+	}
+
+	if operator.Spec.StrictSigningKeyUsage {
+		usr.Status.MarkIssuerResolveFailed(v1alpha1.ReasonUnsupportedIssuer, "operator %s/%s requires strict signing key usage, the user must be issued by a SigningKey of account %s", operator.Namespace, operator.Name, account.Name)
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (r *UserReconciler) reconcileJWTSecret(ctx context.Context, usr *v1alpha1.User, keyPairable v1alpha1.KeyPairable, account *v1alpha1.Account) (string, bool, error) {
 	logger := log.FromContext(ctx)
 
 	issuerKP, ok, err := r.loadIssuerSeed(ctx, keyPairable, nkeys.PrefixByteAccount)
@@ -294,13 +423,29 @@ func (r *UserReconciler) reconcileJWTSecret(ctx context.Context, usr *v1alpha1.U
 		return "", ok, err
 	}
 
+	opts, ok := r.userClaimsOptions(usr, keyPairable, account)
+	if !ok {
+		return "", false, nil
+	}
+
 	usr.Status.MarkIssuerResolved()
 
+	if hasExpiry(usr.Spec) {
+		opts = append(opts, nsc.WithExpiry(time.Now().Add(usr.Spec.Expiry.Duration)))
+	}
+
 	// we want to check that any existing secret decodes to match wantClaims, if it doesn't then we will use nextJWT
 	// to create/update the secret. We cannot just compare the JWTs from the secret and accountJWT because the JWTs are
 	// timestamped with the `iat` claim so will never match.
-	wantClaims, nextJWT, err := nsc.CreateUserClaims(usr, issuerKP)
+	wantClaims, nextJWT, err := nsc.CreateUserClaims(usr, issuerKP, opts...)
 	if err != nil {
+		if isInvalidSpec(err) {
+			// retrying won't help, we need to wait for the spec to be fixed
+			usr.Status.MarkJWTSecretFailed(v1alpha1.ReasonInvalidSpec, err.Error())
+
+			return "", false, nil
+		}
+
 		usr.Status.MarkJWTSecretFailed(v1alpha1.ReasonUnknownError, err.Error())
 
 		return "", false, nil
@@ -326,7 +471,49 @@ func (r *UserReconciler) reconcileJWTSecret(ctx context.Context, usr *v1alpha1.U
 		return "", false, err
 	}
 
-	return r.ensureJWTSecretUpToDate(ctx, usr, wantClaims, got, nextJWT)
+	return r.ensureJWTSecretUpToDate(ctx, usr, account, wantClaims, got, nextJWT)
+}
+
+// userClaimsOptions returns the options required when creating the User JWT for the given issuer. If the issuer is a
+// SigningKey, it must already be present on the Account's signing keys (including any scope) and the Account JWT must
+// have been pushed, otherwise the server would reject the User or apply the wrong permissions until the Account JWT
+// catches up. In this case the IssuerResolved condition is marked as unknown and ok is false, the Account watch will
+// trigger a reconcile once the Account is updated.
+func (r *UserReconciler) userClaimsOptions(usr *v1alpha1.User, keyPairable v1alpha1.KeyPairable, account *v1alpha1.Account) (opts []nsc.UserClaimsOption, ok bool) {
+	sk, isSigningKey := keyPairable.(*v1alpha1.SigningKey)
+	if !isSigningKey {
+		return nil, true
+	}
+
+	var embedded *v1alpha1.SigningKeyEmbeddedStatus
+
+	for i, v := range account.Status.SigningKeys {
+		if v.KeyPair.PublicKey == sk.Status.KeyPair.PublicKey {
+			embedded = &account.Status.SigningKeys[i]
+
+			break
+		}
+	}
+
+	if embedded == nil || !equality.Semantic.DeepEqual(embedded.Scope, sk.Spec.Scope) {
+		usr.Status.MarkIssuerResolveUnknown(v1alpha1.ReasonNotReady, "signing key %s is not yet up-to-date on account %s", sk.Name, account.Name)
+
+		return nil, false
+	}
+
+	if !account.Status.GetCondition(v1alpha1.AccountConditionJWTPushed).IsTrue() {
+		usr.Status.MarkIssuerResolveUnknown(v1alpha1.ReasonNotReady, "waiting for account %s JWT to be pushed", account.Name)
+
+		return nil, false
+	}
+
+	opts = append(opts, nsc.WithIssuerAccount(account.Status.KeyPair.PublicKey))
+
+	if embedded.Scope != nil {
+		opts = append(opts, nsc.WithScopedSigner())
+	}
+
+	return opts, true
 }
 
 func (r *UserReconciler) createJWTSecret(ctx context.Context, usr *v1alpha1.User, userJWT string) (bool, error) {
@@ -358,7 +545,7 @@ func (r *UserReconciler) createJWTSecret(ctx context.Context, usr *v1alpha1.User
 
 // ensureJWTSecretUpToDate compares that the existing JWT secret decodes and matches the expected claims, if it does not
 // match the secret will be updated with the nextJWT value.
-func (r *UserReconciler) ensureJWTSecretUpToDate(ctx context.Context, usr *v1alpha1.User, wantClaims *jwt.UserClaims, got *v1.Secret, nextJWT string) (string, bool, error) {
+func (r *UserReconciler) ensureJWTSecretUpToDate(ctx context.Context, usr *v1alpha1.User, account *v1alpha1.Account, wantClaims *jwt.UserClaims, got *v1.Secret, nextJWT string) (string, bool, error) {
 	logger := log.FromContext(ctx)
 
 	gotJWT, ok := got.Data[v1alpha1.NatsSecretJWTKey]
@@ -374,8 +561,14 @@ func (r *UserReconciler) ensureJWTSecretUpToDate(ctx context.Context, usr *v1alp
 	switch {
 	case err != nil:
 		logger.Info("failed to decode JWT from secret, updating to latest version", "reason", err.Error())
-	case !nsc.Equality.DeepEqual(gotClaims, wantClaims):
+	case !userClaimsEqual(gotClaims, wantClaims):
 		logger.V(1).Info("existing JWT secret does not match desired claims, updating to latest version")
+	case !isTrustedIssuer(account, gotClaims.Claims().Issuer):
+		logger.Info("existing JWT was issued by a key no longer trusted by the account, updating to latest version", "issuer", gotClaims.Claims().Issuer)
+	case isRevoked(account, gotClaims.Claims()):
+		logger.Info("existing JWT has been revoked by the account, updating to latest version")
+	case jwtDueForRenewal(usr.Spec, gotClaims.Claims(), time.Now()):
+		logger.Info("existing JWT is due for renewal, updating to latest version")
 	default:
 		logger.V(1).Info("existing JWT secret matches desired claims, no update required")
 
@@ -409,6 +602,113 @@ func (r *UserReconciler) ensureJWTSecretUpToDate(ctx context.Context, usr *v1alp
 	return nextJWT, true, nil
 }
 
+// isRevoked returns true if the Account has revoked the subject of claims at or after the time they were issued, e.g.
+// because the User was previously not allowed by the Account.
+func isRevoked(account *v1alpha1.Account, claims *jwt.ClaimsData) bool {
+	for _, revocation := range account.Status.Revocations {
+		if revocation.PublicKey == claims.Subject && claims.IssuedAt <= revocation.RevokedAt.Unix() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTrustedIssuer returns true if publicKey is the Account's identity key or one of its signing keys, otherwise JWTs
+// issued by publicKey would be rejected by the server.
+func isTrustedIssuer(account *v1alpha1.Account, publicKey string) bool {
+	if account.Status.KeyPair != nil && account.Status.KeyPair.PublicKey == publicKey {
+		return true
+	}
+
+	for _, sk := range account.Status.SigningKeys {
+		if sk.KeyPair.PublicKey == publicKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasExpiry returns true if JWTs issued for the User should expire.
+func hasExpiry(spec v1alpha1.UserSpec) bool {
+	return spec.Expiry != nil && spec.Expiry.Duration > 0
+}
+
+// renewBefore returns how long before expiry the User JWT should be re-issued, defaulting to a third of the expiry
+// if RenewBefore is not set or is not shorter than the expiry.
+func renewBefore(spec v1alpha1.UserSpec) time.Duration {
+	expiry := spec.Expiry.Duration
+
+	if spec.RenewBefore != nil && spec.RenewBefore.Duration > 0 && spec.RenewBefore.Duration < expiry {
+		return spec.RenewBefore.Duration
+	}
+
+	return expiry / 3
+}
+
+// userClaimsEqual compares got with want using nsc.Equality, except that the expiry timestamps are only compared by
+// whether they are set. They are relative to when each JWT was issued so never match, instead jwtDueForRenewal checks
+// whether the existing JWT needs to be re-issued before it expires.
+func userClaimsEqual(got jwt.Claims, want *jwt.UserClaims) bool {
+	gotUser, ok := got.(*jwt.UserClaims)
+	if !ok {
+		return false
+	}
+
+	if gotUser.Expires != 0 && want.Expires != 0 {
+		expiring := *want
+		expiring.Expires = gotUser.Expires
+		want = &expiring
+	}
+
+	return nsc.Equality.DeepEqual(gotUser, want)
+}
+
+// jwtDueForRenewal returns true if the JWT described by claims has reached its renewal time, or if it expires later
+// than the User's current expiry would allow.
+func jwtDueForRenewal(spec v1alpha1.UserSpec, claims *jwt.ClaimsData, now time.Time) bool {
+	if !hasExpiry(spec) || claims.Expires == 0 {
+		return false
+	}
+
+	expiresAt := time.Unix(claims.Expires, 0)
+
+	return !now.Before(expiresAt.Add(-renewBefore(spec))) || expiresAt.After(now.Add(spec.Expiry.Duration))
+}
+
+// markJWTClaims records which key issued the User JWT, when it expires and when it will be renewed, returning the
+// duration until the renewal is due, or zero if the JWT does not expire.
+func markJWTClaims(usr *v1alpha1.User, ujwt string) time.Duration {
+	usr.Status.IssuerKey = ""
+	usr.Status.ExpiresAt = nil
+	usr.Status.RenewAt = nil
+
+	claims, err := jwt.DecodeUserClaims(ujwt)
+	if err != nil {
+		return 0
+	}
+
+	usr.Status.IssuerKey = claims.Issuer
+
+	if !hasExpiry(usr.Spec) || claims.Expires == 0 {
+		return 0
+	}
+
+	expiresAt := metav1.Unix(claims.Expires, 0)
+	renewAt := metav1.NewTime(expiresAt.Add(-renewBefore(usr.Spec)).Truncate(time.Second))
+
+	usr.Status.ExpiresAt = &expiresAt
+	usr.Status.RenewAt = &renewAt
+
+	renewAfter := time.Until(renewAt.Time)
+	if renewAfter <= 0 {
+		renewAfter = time.Second
+	}
+
+	return renewAfter
+}
+
 func (r *UserReconciler) reconcileUserCredentialSecret(ctx context.Context, usr *v1alpha1.User, ujwt string, seed []byte) error {
 	logger := log.FromContext(ctx)
 
@@ -496,12 +796,249 @@ func (r *UserReconciler) ensureCredentialsSecretUpToDate(ctx context.Context, us
 	return nil
 }
 
+// finalizeUser revokes the User's public key within its Account so that any JWTs issued for the User can no longer be
+// used to connect.
+func (r *UserReconciler) finalizeUser(ctx context.Context, usr *v1alpha1.User) error {
+	logger := log.FromContext(ctx)
+
+	accountRef := issuedAccountRef(&usr.Status)
+	if usr.Status.KeyPair == nil || accountRef == nil {
+		logger.Info("user has no keypair or account, skipping finalization")
+
+		return nil
+	}
+
+	return r.revokeUserKey(ctx, usr, *accountRef, usr.Status.KeyPair.PublicKey)
+}
+
+// revokeIssuedKey revokes the User's public key within the Account which issued its credentials once the Account no
+// longer allows the User. The Account is then forgotten, so the key is not revoked again when the User is deleted.
+func (r *UserReconciler) revokeIssuedKey(ctx context.Context, usr *v1alpha1.User, accountRef *v1alpha1.InferredObjectReference) error {
+	if usr.Status.KeyPair == nil || accountRef == nil {
+		return nil
+	}
+
+	if err := r.revokeUserKey(ctx, usr, *accountRef, usr.Status.KeyPair.PublicKey); err != nil {
+		usr.Status.IssuedAccountRef = accountRef
+
+		return err
+	}
+
+	usr.Status.IssuedAccountRef = nil
+
+	return nil
+}
+
+// issuedAccountRef returns the Account which issued the JWT held for the User. Users whose JWT was issued before
+// .status.issuedAccountRef was recorded fall back to .status.accountRef.
+func issuedAccountRef(status *v1alpha1.UserStatus) *v1alpha1.InferredObjectReference {
+	if status.IssuedAccountRef != nil {
+		return status.IssuedAccountRef
+	}
+
+	return status.AccountRef
+}
+
+// revokeUserKey adds publicKey to the revocations of the Account referenced by accountRef. The Account controller is
+// then responsible for including the revocation in the Account JWT and pushing it to the account server.
+func (r *UserReconciler) revokeUserKey(ctx context.Context, usr *v1alpha1.User, accountRef v1alpha1.InferredObjectReference, publicKey string) error {
+	logger := log.FromContext(ctx)
+
+	revocation := v1alpha1.UserRevocation{
+		PublicKey: publicKey,
+		RevokedAt: metav1.Now(),
+		ExpiresAt: r.getUserJWTExpiry(ctx, usr, publicKey),
+		UserRef: v1alpha1.InferredObjectReference{
+			Namespace: usr.Namespace,
+			Name:      usr.Name,
+		},
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		account, err := r.AccountsClientSet.Accounts(accountRef.Namespace).Get(ctx, accountRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		for _, existing := range account.Status.Revocations {
+			if existing.PublicKey == publicKey {
+				return nil
+			}
+		}
+
+		account.Status.Revocations = append(account.Status.Revocations, revocation)
+
+		_, err = r.AccountsClientSet.Accounts(accountRef.Namespace).UpdateStatus(ctx, account, metav1.UpdateOptions{})
+
+		return err
+	})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("account not found, skipping revocation", "account", accountRef.Name, "namespace", accountRef.Namespace)
+
+			return nil
+		}
+
+		logger.Error(err, "failed to revoke user key", "account", accountRef.Name, "namespace", accountRef.Namespace)
+
+		return err
+	}
+
+	r.EventRecorder.Eventf(usr, v1.EventTypeNormal, "UserKeyRevoked", "revoked public key %s in account %s/%s", publicKey, accountRef.Namespace, accountRef.Name)
+
+	return nil
+}
+
+// getUserJWTExpiry returns the expiry of the JWT currently stored for the User if it was issued for publicKey. A nil
+// value is returned if the JWT cannot be loaded or has no expiry.
+func (r *UserReconciler) getUserJWTExpiry(ctx context.Context, usr *v1alpha1.User, publicKey string) *metav1.Time {
+	logger := log.FromContext(ctx)
+
+	secret, err := r.CoreV1.Secrets(usr.Namespace).Get(ctx, usr.Spec.JWTSecretName, metav1.GetOptions{})
+	if err != nil {
+		logger.V(1).Info("unable to load user JWT secret, assuming JWT does not expire", "reason", err.Error())
+
+		return nil
+	}
+
+	claims, err := jwt.DecodeUserClaims(string(secret.Data[v1alpha1.NatsSecretJWTKey]))
+	if err != nil || claims.Subject != publicKey || claims.Expires == 0 {
+		return nil
+	}
+
+	expiresAt := metav1.NewTime(time.Unix(claims.Expires, 0))
+
+	return &expiresAt
+}
+
+// mapNamespaceToUsers enqueues all Users within a Namespace, this is used to re-evaluate Users against their Account's
+// UsersNamespaceSelector whenever the labels on a Namespace change.
+func (r *UserReconciler) mapNamespaceToUsers(obj client.Object) []reconcile.Request {
+	users := new(v1alpha1.UserList)
+	if err := r.List(context.Background(), users, client.InNamespace(obj.GetName())); err != nil {
+		r.logger.Error(err, "failed to list users in namespace", "namespace", obj.GetName())
+
+		return nil
+	}
+
+	return userRequests(users.Items)
+}
+
+// mapAccountToUsers enqueues all Users which are, or may be, issued by an Account. This includes Users which reference
+// the Account directly, Users which reference a SigningKey owned by the Account, and Users which have previously
+// resolved the Account.
+func (r *UserReconciler) mapAccountToUsers(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+
+	account, ok := obj.(*v1alpha1.Account)
+	if !ok {
+		r.logger.Info("Account watcher received non-Account object",
+			"kind", obj.GetObjectKind().GroupVersionKind().String())
+
+		return nil
+	}
+
+	matchingFields := []client.MatchingFields{
+		{indexUserIssuer: issuerIndexKey("Account", account.Namespace, account.Name)},
+		{indexUserAccountRef: namespacedIndexKey(account.Namespace, account.Name)},
+	}
+
+	signingKeys := new(v1alpha1.SigningKeyList)
+	if err := r.List(ctx, signingKeys, client.InNamespace(account.Namespace)); err != nil {
+		r.logger.Error(err, "failed to list signing keys", "namespace", account.Namespace)
+
+		return nil
+	}
+
+	for _, sk := range signingKeys.Items {
+		if sk.Status.OwnerRef == nil || sk.Status.OwnerRef.UID != account.UID {
+			continue
+		}
+
+		matchingFields = append(matchingFields, client.MatchingFields{
+			indexUserIssuer: issuerIndexKey("SigningKey", sk.Namespace, sk.Name),
+		})
+	}
+
+	var matches []v1alpha1.User
+
+	for _, fields := range matchingFields {
+		users := new(v1alpha1.UserList)
+		if err := r.List(ctx, users, fields); err != nil {
+			r.logger.Error(err, "failed to list users for account", "account", account.Name, "namespace", account.Namespace)
+
+			return nil
+		}
+
+		matches = append(matches, users.Items...)
+	}
+
+	return userRequests(matches)
+}
+
+// mapSigningKeyToUsers enqueues all Users issued by a SigningKey, so their JWTs are re-issued when it is rotated,
+// re-keyed or deleted.
+func (r *UserReconciler) mapSigningKeyToUsers(obj client.Object) []reconcile.Request {
+	users := new(v1alpha1.UserList)
+	if err := r.List(context.Background(), users, client.MatchingFields{
+		indexUserIssuer: issuerIndexKey("SigningKey", obj.GetNamespace(), obj.GetName()),
+	}); err != nil {
+		r.logger.Error(err, "failed to list users for signing key", "signing_key", obj.GetName(), "namespace", obj.GetNamespace())
+
+		return nil
+	}
+
+	return userRequests(users.Items)
+}
+
+// userRequests converts a list of Users to a de-duplicated list of reconcile.Requests.
+func userRequests(users []v1alpha1.User) []reconcile.Request {
+	seen := make(map[types.NamespacedName]bool, len(users))
+	requests := make([]reconcile.Request, 0, len(users))
+
+	for _, usr := range users {
+		key := types.NamespacedName{Namespace: usr.Namespace, Name: usr.Name}
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		requests = append(requests, reconcile.Request{NamespacedName: key})
+	}
+
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *UserReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.EventRecorder = mgr.GetEventRecorderFor("user-controller")
+	r.logger = mgr.GetLogger().WithName("UserReconciler")
+
+	ctx := context.Background()
+
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1alpha1.User{}, indexUserIssuer, indexUserByIssuer); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1alpha1.User{}, indexUserAccountRef, indexUserByAccountRef); err != nil {
+		return err
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.User{}).
 		Owns(&v1.Secret{}).
+		Watches(
+			&source.Kind{Type: &v1.Namespace{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapNamespaceToUsers),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.Account{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapAccountToUsers),
+		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.SigningKey{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapSigningKeyToUsers),
+		).
 		Complete(r)
 }
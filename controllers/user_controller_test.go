@@ -0,0 +1,405 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nats-io/jwt/v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// newTestUserReconciler returns a UserReconciler holding the given objects.
+func newTestUserReconciler(objects ...client.Object) *UserReconciler {
+	c := newFakeClient(objects...)
+	recorder := record.NewFakeRecorder(100)
+
+	return &UserReconciler{
+		BaseReconciler: &BaseReconciler{
+			Client:        c,
+			APIReader:     c,
+			Scheme:        newTestScheme(),
+			CoreV1:        newFakeCoreV1(),
+			EventRecorder: recorder,
+		},
+		AccountsClientSet: fakeAccountsClientSet{client: c},
+		EventRecorder:     recorder,
+	}
+}
+
+// newTestIssuedUser returns a User in the tenant namespace whose JWT with publicKey was issued by the Account acc.
+func newTestIssuedUser(publicKey string) *v1alpha1.User {
+	usr := &v1alpha1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "usr", Namespace: tenantNamespace},
+	}
+
+	ref := v1alpha1.InferredObjectReference{Namespace: testNamespace, Name: "acc"}
+
+	usr.Status.InitializeConditions()
+	usr.Status.MarkSeedSecretReady(publicKey, "usr-seed")
+	usr.Status.MarkAccountResolved(ref)
+	usr.Status.IssuedAccountRef = &ref
+
+	return usr
+}
+
+// newTestResolvedAccount returns an Account issued by the Operator op which has resolved it.
+func newTestResolvedAccount() *v1alpha1.Account {
+	account := newTestAccount("acc", "Operator", "op", "AACC")
+
+	account.Status.InitializeConditions()
+	account.Status.MarkOperatorResolved(v1alpha1.InferredObjectReference{Namespace: testNamespace, Name: "op"})
+
+	return account
+}
+
+// revokedKeys returns the public keys revoked by the Account acc.
+func revokedKeys(t *testing.T, c client.Client) []string {
+	t.Helper()
+
+	acc := &v1alpha1.Account{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: testNamespace, Name: "acc"}, acc); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for _, revocation := range acc.Status.Revocations {
+		keys = append(keys, revocation.PublicKey)
+	}
+
+	return keys
+}
+
+func TestResolveAccountRevokesRejectedUser(t *testing.T) {
+	ctx := context.Background()
+
+	account := newTestResolvedAccount()
+	account.Spec.UsersNamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}}
+
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tenantNamespace, Labels: map[string]string{"tenant": "true"}}}
+
+	r := newTestUserReconciler(newTestOperator(), account, ns)
+	usr := newTestIssuedUser("UUSR")
+
+	if _, ok, err := r.resolveAccount(ctx, usr, account); err != nil || !ok {
+		t.Fatalf("resolveAccount() = %t, %v, want the user to be allowed", ok, err)
+	}
+
+	if keys := revokedKeys(t, r.Client); len(keys) != 0 {
+		t.Fatalf("resolveAccount() revoked %v for an allowed user", keys)
+	}
+
+	// the namespace is relabelled after the user credentials were issued
+	ns.Labels = nil
+
+	if err := r.Update(ctx, ns); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := r.resolveAccount(ctx, usr, account); err != nil || ok {
+		t.Fatalf("resolveAccount() = %t, %v, want the user to be rejected", ok, err)
+	}
+
+	if diff := cmp.Diff([]string{"UUSR"}, revokedKeys(t, r.Client)); diff != "" {
+		t.Errorf("revoked keys mismatch (-want +got):\n%s", diff)
+	}
+
+	if usr.Status.IssuedAccountRef != nil {
+		t.Errorf("IssuedAccountRef = %v, want it cleared once the key is revoked", usr.Status.IssuedAccountRef)
+	}
+
+	if err := r.finalizeUser(ctx, usr); err != nil {
+		t.Fatalf("finalizeUser() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"UUSR"}, revokedKeys(t, r.Client)); diff != "" {
+		t.Errorf("revoked keys after finalization mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestResolveAccountStrictSigningKeyUsage(t *testing.T) {
+	ctx := context.Background()
+
+	operator := newTestOperator()
+	operator.Spec.StrictSigningKeyUsage = true
+
+	account := newTestResolvedAccount()
+
+	r := newTestUserReconciler(operator, account)
+
+	usr := &v1alpha1.User{ObjectMeta: metav1.ObjectMeta{Name: "usr", Namespace: testNamespace}}
+	usr.Status.InitializeConditions()
+
+	if _, ok, err := r.resolveAccount(ctx, usr, account); err != nil || ok {
+		t.Fatalf("resolveAccount() = %t, %v, want the account identity key to be rejected", ok, err)
+	}
+
+	if c := usr.Status.GetCondition(v1alpha1.UserConditionIssuerResolved); !c.IsFalse() || c.Reason != v1alpha1.ReasonUnsupportedIssuer {
+		t.Errorf("IssuerResolved condition = %v, want False with reason %s", c, v1alpha1.ReasonUnsupportedIssuer)
+	}
+}
+
+func TestFinalizeUserWithoutAccountRef(t *testing.T) {
+	ctx := context.Background()
+
+	r := newTestUserReconciler(newTestAccount("acc", "Operator", "op", "AACC"))
+
+	// the account became unresolvable after the credentials were issued
+	usr := newTestIssuedUser("UUSR")
+	usr.Status.MarkAccountResolveUnknown(v1alpha1.ReasonNotReady, "account is not ready")
+
+	if err := r.finalizeUser(ctx, usr); err != nil {
+		t.Fatalf("finalizeUser() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"UUSR"}, revokedKeys(t, r.Client)); diff != "" {
+		t.Errorf("revoked keys mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIsRevoked(t *testing.T) {
+	revokedAt := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	account := newTestAccount("acc", "Operator", "op", "AACC")
+	account.Status.Revocations = []v1alpha1.UserRevocation{{PublicKey: "UREVOKED", RevokedAt: metav1.NewTime(revokedAt)}}
+
+	tests := []struct {
+		name     string
+		subject  string
+		issuedAt time.Time
+		want     bool
+	}{
+		{name: "issued before the revocation", subject: "UREVOKED", issuedAt: revokedAt.Add(-time.Hour), want: true},
+		{name: "issued at the revocation", subject: "UREVOKED", issuedAt: revokedAt, want: true},
+		{name: "issued after the revocation", subject: "UREVOKED", issuedAt: revokedAt.Add(time.Second), want: false},
+		{name: "other key", subject: "UOTHER", issuedAt: revokedAt.Add(-time.Hour), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &jwt.ClaimsData{Subject: tt.subject, IssuedAt: tt.issuedAt.Unix()}
+
+			if got := isRevoked(account, claims); got != tt.want {
+				t.Errorf("isRevoked() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenewBefore(t *testing.T) {
+	tests := []struct {
+		name        string
+		expiry      time.Duration
+		renewBefore *metav1.Duration
+		want        time.Duration
+	}{
+		{name: "defaults to a third of the expiry", expiry: 3 * time.Hour, want: time.Hour},
+		{name: "zero renew before", expiry: 3 * time.Hour, renewBefore: &metav1.Duration{}, want: time.Hour},
+		{name: "shorter than the expiry", expiry: 3 * time.Hour, renewBefore: &metav1.Duration{Duration: 10 * time.Minute}, want: 10 * time.Minute},
+		{name: "equal to the expiry", expiry: 3 * time.Hour, renewBefore: &metav1.Duration{Duration: 3 * time.Hour}, want: time.Hour},
+		{name: "longer than the expiry", expiry: 3 * time.Hour, renewBefore: &metav1.Duration{Duration: 4 * time.Hour}, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := v1alpha1.UserSpec{Expiry: &metav1.Duration{Duration: tt.expiry}, RenewBefore: tt.renewBefore}
+
+			if got := renewBefore(spec); got != tt.want {
+				t.Errorf("renewBefore() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJWTDueForRenewal(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	expiring := v1alpha1.UserSpec{
+		Expiry:      &metav1.Duration{Duration: 3 * time.Hour},
+		RenewBefore: &metav1.Duration{Duration: time.Hour},
+	}
+
+	tests := []struct {
+		name      string
+		spec      v1alpha1.UserSpec
+		expiresAt time.Time
+		want      bool
+	}{
+		{name: "user does not expire", spec: v1alpha1.UserSpec{}, expiresAt: now.Add(-time.Hour), want: false},
+		{name: "jwt does not expire", spec: expiring, want: false},
+		{name: "before the renewal time", spec: expiring, expiresAt: now.Add(2 * time.Hour), want: false},
+		{name: "at the renewal time", spec: expiring, expiresAt: now.Add(time.Hour), want: true},
+		{name: "expired", spec: expiring, expiresAt: now.Add(-time.Minute), want: true},
+		{name: "at the maximum expiry", spec: expiring, expiresAt: now.Add(3 * time.Hour), want: false},
+		{name: "later than the expiry allows", spec: expiring, expiresAt: now.Add(4 * time.Hour), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &jwt.ClaimsData{}
+			if !tt.expiresAt.IsZero() {
+				claims.Expires = tt.expiresAt.Unix()
+			}
+
+			if got := jwtDueForRenewal(tt.spec, claims, now); got != tt.want {
+				t.Errorf("jwtDueForRenewal() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserClaimsEqual(t *testing.T) {
+	newClaims := func(expires int64, sub ...string) *jwt.UserClaims {
+		claims := jwt.NewUserClaims("UUSR")
+		claims.Expires = expires
+		claims.Sub.Allow = sub
+
+		return claims
+	}
+
+	tests := []struct {
+		name string
+		got  *jwt.UserClaims
+		want *jwt.UserClaims
+		eq   bool
+	}{
+		{name: "equal", got: newClaims(0), want: newClaims(0), eq: true},
+		{name: "different expiry timestamps", got: newClaims(100), want: newClaims(200), eq: true},
+		{name: "expiry added", got: newClaims(0), want: newClaims(200), eq: false},
+		{name: "expiry removed", got: newClaims(100), want: newClaims(0), eq: false},
+		{name: "different permissions", got: newClaims(100, "foo"), want: newClaims(200, "bar"), eq: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := *tt.want
+
+			if got := userClaimsEqual(tt.got, tt.want); got != tt.eq {
+				t.Errorf("userClaimsEqual() = %t, want %t", got, tt.eq)
+			}
+
+			if diff := cmp.Diff(want, *tt.want); diff != "" {
+				t.Errorf("userClaimsEqual() modified the desired claims (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUserClaimsOptions(t *testing.T) {
+	scope := &v1alpha1.SigningKeyScope{Role: "reader"}
+
+	newSigningKey := func(scope *v1alpha1.SigningKeyScope) *v1alpha1.SigningKey {
+		sk := &v1alpha1.SigningKey{ObjectMeta: metav1.ObjectMeta{Name: "acc-sk", Namespace: testNamespace}}
+		sk.Spec.Scope = scope
+		sk.Status.KeyPair = &v1alpha1.KeyPair{PublicKey: "ASIGNINGKEY"}
+
+		return sk
+	}
+
+	newAccount := func(pushed bool, signingKeys ...v1alpha1.SigningKeyEmbeddedStatus) *v1alpha1.Account {
+		account := newTestAccount("acc", "Operator", "op", "AACC")
+		account.Status.InitializeConditions()
+		account.Status.SigningKeys = signingKeys
+
+		if pushed {
+			account.Status.MarkJWTPushed()
+		}
+
+		return account
+	}
+
+	unscopedKey := v1alpha1.SigningKeyEmbeddedStatus{Name: "acc-sk", KeyPair: v1alpha1.KeyPair{PublicKey: "ASIGNINGKEY"}}
+	scopedKey := v1alpha1.SigningKeyEmbeddedStatus{Name: "acc-sk", KeyPair: v1alpha1.KeyPair{PublicKey: "ASIGNINGKEY"}, Scope: scope}
+
+	tests := []struct {
+		name              string
+		issuer            v1alpha1.KeyPairable
+		account           *v1alpha1.Account
+		wantOK            bool
+		wantIssuerAccount string
+		wantPermissions   bool
+	}{
+		{
+			name:            "account identity key",
+			issuer:          newAccount(true),
+			account:         newAccount(true),
+			wantOK:          true,
+			wantPermissions: true,
+		},
+		{
+			name:    "signing key missing from the account",
+			issuer:  newSigningKey(nil),
+			account: newAccount(true),
+		},
+		{
+			name:    "account jwt containing the signing key not pushed",
+			issuer:  newSigningKey(nil),
+			account: newAccount(false, unscopedKey),
+		},
+		{
+			name:    "signing key scope not up to date on the account",
+			issuer:  newSigningKey(scope),
+			account: newAccount(true, unscopedKey),
+		},
+		{
+			name:              "signing key",
+			issuer:            newSigningKey(nil),
+			account:           newAccount(true, unscopedKey),
+			wantOK:            true,
+			wantIssuerAccount: "AACC",
+			wantPermissions:   true,
+		},
+		{
+			name:              "scoped signing key",
+			issuer:            newSigningKey(scope),
+			account:           newAccount(true, scopedKey),
+			wantOK:            true,
+			wantIssuerAccount: "AACC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usr := &v1alpha1.User{ObjectMeta: metav1.ObjectMeta{Name: "usr", Namespace: testNamespace}}
+			usr.Status.InitializeConditions()
+
+			opts, ok := (&UserReconciler{}).userClaimsOptions(usr, tt.issuer, tt.account)
+			if ok != tt.wantOK {
+				t.Fatalf("userClaimsOptions() ok = %t, want %t", ok, tt.wantOK)
+			}
+
+			if !ok {
+				if c := usr.Status.GetCondition(v1alpha1.UserConditionIssuerResolved); !c.IsUnknown() || c.Reason != v1alpha1.ReasonNotReady {
+					t.Errorf("IssuerResolved condition = %v, want Unknown with reason %s", c, v1alpha1.ReasonNotReady)
+				}
+
+				if usr.Status.IsReady() {
+					t.Errorf("user is Ready before the account JWT containing its issuer is pushed")
+				}
+
+				return
+			}
+
+			claims := jwt.NewUserClaims("UUSR")
+			claims.Pub.Allow.Add("orders.>")
+
+			for _, opt := range opts {
+				opt(claims)
+			}
+
+			if claims.IssuerAccount != tt.wantIssuerAccount {
+				t.Errorf("IssuerAccount = %q, want %q", claims.IssuerAccount, tt.wantIssuerAccount)
+			}
+
+			if got := !claims.HasEmptyPermissions(); got != tt.wantPermissions {
+				t.Errorf("user has permissions = %t, want %t", got, tt.wantPermissions)
+			}
+		})
+	}
+}
@@ -26,10 +26,13 @@ SOFTWARE.
 package main
 
 import (
+	"context"
 	"flag"
 	"github.com/versori-oss/nats-account-operator/pkg/nsc"
 	"go.uber.org/zap/zapcore"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -38,15 +41,20 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	accountsnatsiov1alpha1 "github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	accountsnatsiov1beta1 "github.com/versori-oss/nats-account-operator/api/accounts/v1beta1"
 	"github.com/versori-oss/nats-account-operator/controllers"
+	"github.com/versori-oss/nats-account-operator/pkg/accountserver"
 	accountsclientsets "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned"
+	"github.com/versori-oss/nats-account-operator/webhooks"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -59,6 +67,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(accountsnatsiov1alpha1.AddToScheme(scheme))
+	utilruntime.Must(accountsnatsiov1beta1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -66,11 +75,26 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var verifyInterval time.Duration
+	var pruneInterval time.Duration
+	var accountServerAddr string
+	var accountServerOperator string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&verifyInterval, "jwt-verify-interval", 5*time.Minute,
+		"How often Account JWTs held by the NATS servers are verified and pushed again if missing or outdated. "+
+			"Set to 0 to disable verification.")
+	flag.DurationVar(&pruneInterval, "prune-interval", 10*time.Minute,
+		"How often NATS servers are checked for unmanaged Account JWTs, for Operators with pruning enabled. "+
+			"Set to 0 to disable pruning.")
+	flag.StringVar(&accountServerAddr, "account-server-bind-address", "",
+		"The address the built-in account server binds to, serving JWTs for NATS servers using a URL resolver. "+
+			"The account server is disabled if not set.")
+	flag.StringVar(&accountServerOperator, "account-server-operator", "",
+		"The Operator to serve JWTs for from the built-in account server, formatted as <namespace>/<name>.")
 	opts := zap.Options{
 		Development:     true,
 		StacktraceLevel: zapcore.FatalLevel,
@@ -109,32 +133,53 @@ func main() {
 	accountsClientSet := accountsclientsets.NewForConfigOrDie(cfg)
 
 	clientSet.AuthorizationV1()
+
+	// NATS connections are shared between controllers and only closed once the manager stops
+	clientPool := nsc.NewClientPool()
+	if err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		clientPool.Close()
+		return nil
+	})); err != nil {
+		setupLog.Error(err, "unable to add client pool to manager")
+		os.Exit(1)
+	}
+
+	sysUserLoader := nsc.NewSystemUserLoader(accountsClientSet.AccountsV1alpha1(), clientSet.CoreV1())
+
 	if err = (&controllers.OperatorReconciler{
 		Client:            mgr.GetClient(),
 		Scheme:            mgr.GetScheme(),
 		CV1Interface:      clientSet.CoreV1(),
 		AccountsClientSet: accountsClientSet.AccountsV1alpha1(),
+		SysUserLoader:     sysUserLoader,
+		ClientPool:        clientPool,
+		PruneInterval:     pruneInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Operator")
 		os.Exit(1)
 	}
 	if err = (&controllers.AccountReconciler{
 		BaseReconciler: &controllers.BaseReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
-			CoreV1: clientSet.CoreV1(),
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Scheme:    mgr.GetScheme(),
+			CoreV1:    clientSet.CoreV1(),
 		},
 		AccountsV1Alpha1: accountsClientSet.AccountsV1alpha1(),
-		SysAccountLoader: nsc.NewSystemAccountLoader(accountsClientSet.AccountsV1alpha1(), clientSet.CoreV1()),
+		SysUserLoader:    sysUserLoader,
+		ClientPool:       clientPool,
+		VerifyInterval:   verifyInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Account")
 		os.Exit(1)
 	}
 	if err = (&controllers.UserReconciler{
 		BaseReconciler: &controllers.BaseReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
-			CoreV1: clientSet.CoreV1(),
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Scheme:    mgr.GetScheme(),
+			CoreV1:    clientSet.CoreV1(),
 		},
 		AccountsClientSet: accountsClientSet.AccountsV1alpha1(),
 	}).SetupWithManager(mgr); err != nil {
@@ -150,8 +195,29 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "SigningKey")
 		os.Exit(1)
 	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooks.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhooks")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
+	if accountServerAddr != "" {
+		namespace, name, ok := strings.Cut(accountServerOperator, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(nil, "--account-server-operator must be formatted as <namespace>/<name>", "value", accountServerOperator)
+			os.Exit(1)
+		}
+
+		operator := types.NamespacedName{Namespace: namespace, Name: name}
+
+		if err = accountserver.New(accountServerAddr, operator).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up account server")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -0,0 +1,369 @@
+package accountserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+const (
+	// PathAccounts is the prefix for account JWT requests, NATS servers should be configured with
+	// `resolver: URL(<address>/jwt/v1/accounts/)`.
+	PathAccounts = "/jwt/v1/accounts/"
+
+	// PathOperator returns the Operator JWT.
+	PathOperator = "/jwt/v1/operator"
+
+	// PathHealth returns 200 once the server is ready to serve JWTs.
+	PathHealth = "/healthz"
+
+	// MaxWait is the longest a client may wait for a JWT to change when long-polling.
+	MaxWait = 5 * time.Minute
+
+	// indexAccountPublicKey indexes Accounts by .status.keyPair.publicKey.
+	indexAccountPublicKey = ".status.keyPair.publicKey"
+)
+
+// Server serves the nats-account-server HTTP protocol for a single Operator, reading the Operator and Account JWTs
+// from their Secrets through the manager's cache. This allows NATS servers to use a URL resolver pointing at the
+// manager instead of having JWTs pushed to them.
+//
+// Clients may long-poll for changes to a JWT by sending the ETag of the JWT they hold in an If-None-Match header along
+// with a `wait` query parameter, such as `?wait=30s`. The request returns as soon as the JWT changes, or with
+// 304 Not Modified once the wait has elapsed.
+type Server struct {
+	addr     string
+	operator types.NamespacedName
+
+	client client.Reader
+	cache  cache.Cache
+	logger logr.Logger
+
+	mu      sync.Mutex
+	changed chan struct{}
+	synced  bool
+}
+
+// New returns a Server which listens on addr and serves JWTs for the named Operator.
+func New(addr string, operator types.NamespacedName) *Server {
+	return &Server{
+		addr:     addr,
+		operator: operator,
+		logger:   ctrl.Log.WithName("AccountServer"),
+		changed:  make(chan struct{}),
+	}
+}
+
+// SetupWithManager registers the Server with the manager, it is started along with the controllers.
+func (s *Server) SetupWithManager(mgr ctrl.Manager) error {
+	s.client = mgr.GetClient()
+	s.cache = mgr.GetCache()
+	s.logger = mgr.GetLogger().WithName("AccountServer")
+
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Account{}, indexAccountPublicKey, indexAccountByPublicKey)
+	if err != nil {
+		return err
+	}
+
+	return mgr.Add(s)
+}
+
+func indexAccountByPublicKey(obj client.Object) []string {
+	acc, ok := obj.(*v1alpha1.Account)
+	if !ok || acc.Status.KeyPair == nil {
+		return nil
+	}
+
+	return []string{acc.Status.KeyPair.PublicKey}
+}
+
+// NeedLeaderElection returns false so that every replica of the manager serves JWTs.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves HTTP requests until ctx is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	// any change to an Account or Secret wakes up long-polling requests, which then compare their JWT with the cache
+	for _, obj := range []client.Object{&v1alpha1.Account{}, &v1alpha1.Operator{}, &v1.Secret{}} {
+		informer, err := s.cache.GetInformer(ctx, obj)
+		if err != nil {
+			return fmt.Errorf("failed to get informer for %T: %w", obj, err)
+		}
+
+		informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    func(interface{}) { s.notify() },
+			UpdateFunc: func(interface{}, interface{}) { s.notify() },
+			DeleteFunc: func(interface{}) { s.notify() },
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathAccounts, s.handleAccount)
+	mux.HandleFunc(PathOperator, s.handleOperator)
+	mux.HandleFunc(PathHealth, s.handleHealth)
+
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if s.cache.WaitForCacheSync(ctx) {
+			s.mu.Lock()
+			s.synced = true
+			s.mu.Unlock()
+		}
+	}()
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		s.logger.Info("starting account server", "addr", s.addr, "operator", s.operator.String())
+
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// notify wakes up all requests waiting for a JWT to change.
+func (s *Server) notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// waitChannel returns a channel which is closed on the next change.
+func (s *Server) waitChannel() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.changed
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	synced := s.synced
+	s.mu.Unlock()
+
+	if !synced {
+		http.Error(w, "cache not synced", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (s *Server) handleOperator(w http.ResponseWriter, r *http.Request) {
+	s.serveJWT(w, r, s.loadOperatorJWT)
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	publicKey := strings.TrimPrefix(r.URL.Path, PathAccounts)
+	if publicKey == "" || strings.Contains(publicKey, "/") {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	s.serveJWT(w, r, func(ctx context.Context) ([]byte, error) {
+		return s.loadAccountJWT(ctx, publicKey)
+	})
+}
+
+// serveJWT writes the JWT returned by load, waiting for it to change first if the request is long-polling.
+func (s *Server) serveJWT(w http.ResponseWriter, r *http.Request, load func(ctx context.Context) ([]byte, error)) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	wait, err := parseWait(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), wait)
+	defer cancel()
+
+	ifNoneMatch := r.Header.Get("If-None-Match")
+
+	for {
+		// take the channel before loading so that a change between loading and waiting is not missed
+		changed := s.waitChannel()
+
+		data, err := load(r.Context())
+
+		var etag string
+		if err == nil {
+			etag = etagFor(data)
+		}
+
+		if wait == 0 || ifNoneMatch == "" || ifNoneMatch != etag {
+			s.writeJWT(w, r, data, etag, err, ifNoneMatch)
+
+			return
+		}
+
+		select {
+		case <-changed:
+			continue
+		case <-ctx.Done():
+			if r.Context().Err() != nil {
+				// the client has gone away
+				return
+			}
+
+			s.writeJWT(w, r, data, etag, err, ifNoneMatch)
+
+			return
+		}
+	}
+}
+
+func (s *Server) writeJWT(w http.ResponseWriter, r *http.Request, data []byte, etag string, err error, ifNoneMatch string) {
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		// the error may include the names of resources in the cluster, which clients should not see
+		s.logger.Error(err, "failed to load JWT", "path", r.URL.Path)
+
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if ifNoneMatch == etag {
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jwt")
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
+
+// parseWait returns the duration the request wants to wait for a change, zero if it is not long-polling.
+func parseWait(r *http.Request) (time.Duration, error) {
+	value := r.URL.Query().Get("wait")
+	if value == "" {
+		return 0, nil
+	}
+
+	wait, err := time.ParseDuration(value)
+	if err != nil || wait < 0 {
+		return 0, fmt.Errorf("invalid wait %q, must be a positive duration", value)
+	}
+
+	if wait > MaxWait {
+		wait = MaxWait
+	}
+
+	return wait, nil
+}
+
+func etagFor(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func (s *Server) loadOperatorJWT(ctx context.Context) ([]byte, error) {
+	operator := new(v1alpha1.Operator)
+	if err := s.client.Get(ctx, s.operator, operator); err != nil {
+		return nil, err
+	}
+
+	return s.loadJWTSecret(ctx, operator.Namespace, operator.Spec.JWTSecretName)
+}
+
+// loadAccountJWT returns the JWT for the Account with the given public key, only Accounts which have resolved to the
+// served Operator are returned.
+func (s *Server) loadAccountJWT(ctx context.Context, publicKey string) ([]byte, error) {
+	accounts := new(v1alpha1.AccountList)
+	if err := s.client.List(ctx, accounts, client.MatchingFields{indexAccountPublicKey: publicKey}); err != nil {
+		return nil, err
+	}
+
+	for _, acc := range accounts.Items {
+		ref := acc.Status.OperatorRef
+		if ref == nil || ref.Namespace != s.operator.Namespace || ref.Name != s.operator.Name {
+			continue
+		}
+
+		if !acc.Status.GetCondition(v1alpha1.AccountConditionJWTSecretReady).IsTrue() {
+			continue
+		}
+
+		return s.loadJWTSecret(ctx, acc.Namespace, acc.Spec.JWTSecretName)
+	}
+
+	return nil, apierrors.NewNotFound(v1alpha1.GroupVersion.WithResource("accounts").GroupResource(), publicKey)
+}
+
+func (s *Server) loadJWTSecret(ctx context.Context, namespace, name string) ([]byte, error) {
+	secret := new(v1.Secret)
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, err
+	}
+
+	data, ok := secret.Data[v1alpha1.NatsSecretJWTKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s missing key %q", namespace, name, v1alpha1.NatsSecretJWTKey)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,357 @@
+package accountserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+const testNamespace = "nats"
+
+// publicKeyIndexClient filters Accounts listed by public key, which the fake client does not support.
+type publicKeyIndexClient struct {
+	client.Client
+}
+
+func (c publicKeyIndexClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := new(client.ListOptions).ApplyOptions(opts)
+
+	selector := listOpts.FieldSelector
+	listOpts.FieldSelector = nil
+
+	if err := c.Client.List(ctx, list, listOpts); err != nil || selector == nil {
+		return err
+	}
+
+	publicKey, _ := selector.RequiresExactMatch(indexAccountPublicKey)
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	matching := make([]runtime.Object, 0, len(items))
+
+	for _, item := range items {
+		for _, key := range indexAccountByPublicKey(item.(client.Object)) {
+			if key == publicKey {
+				matching = append(matching, item)
+			}
+		}
+	}
+
+	return meta.SetList(list, matching)
+}
+
+func newTestServer(objects ...client.Object) *Server {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	s := New("", types.NamespacedName{Namespace: testNamespace, Name: "op"})
+	s.client = publicKeyIndexClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()}
+	s.synced = true
+
+	return s
+}
+
+func newTestOperator() *v1alpha1.Operator {
+	return &v1alpha1.Operator{
+		ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: testNamespace},
+		Spec:       v1alpha1.OperatorSpec{JWTSecretName: "op-jwt"},
+	}
+}
+
+// newTestAccount returns an Account with the given public key which has resolved to operator and whose JWT secret is
+// ready.
+func newTestAccount(name, operator, publicKey string) *v1alpha1.Account {
+	acc := &v1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec:       v1alpha1.AccountSpec{JWTSecretName: name + "-jwt"},
+	}
+
+	acc.Status.InitializeConditions()
+	acc.Status.MarkOperatorResolved(v1alpha1.InferredObjectReference{Namespace: testNamespace, Name: operator})
+	acc.Status.MarkSeedSecretReady(publicKey, name+"-seed")
+	acc.Status.MarkJWTSecretReady()
+
+	return acc
+}
+
+func newTestJWTSecret(name, jwt string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Data:       map[string][]byte{v1alpha1.NatsSecretJWTKey: []byte(jwt)},
+	}
+}
+
+func TestServeJWT(t *testing.T) {
+	notReady := newTestAccount("not-ready", "op", "ANOTREADY")
+	notReady.Status.MarkJWTSecretUnknown("Pending", "")
+
+	s := newTestServer(
+		newTestOperator(),
+		newTestJWTSecret("op-jwt", "operator.jwt"),
+		newTestAccount("acc", "op", "AACC"),
+		newTestJWTSecret("acc-jwt", "acc.jwt"),
+		newTestAccount("other", "other-op", "AOTHER"),
+		newTestJWTSecret("other-jwt", "other.jwt"),
+		notReady,
+		newTestJWTSecret("not-ready-jwt", "not-ready.jwt"),
+		newTestAccount("missing-key", "op", "AMISSINGKEY"),
+		&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "missing-key-jwt", Namespace: testNamespace}},
+	)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		ifNoneMatch string
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:       "operator",
+			path:       PathOperator,
+			wantStatus: http.StatusOK,
+			wantBody:   "operator.jwt",
+		},
+		{
+			name:       "account",
+			path:       PathAccounts + "AACC",
+			wantStatus: http.StatusOK,
+			wantBody:   "acc.jwt",
+		},
+		{
+			name:       "head",
+			method:     http.MethodHead,
+			path:       PathAccounts + "AACC",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "not modified",
+			path:        PathAccounts + "AACC",
+			ifNoneMatch: etagFor([]byte("acc.jwt")),
+			wantStatus:  http.StatusNotModified,
+		},
+		{
+			name:        "modified",
+			path:        PathAccounts + "AACC",
+			ifNoneMatch: etagFor([]byte("previous.jwt")),
+			wantStatus:  http.StatusOK,
+			wantBody:    "acc.jwt",
+		},
+		{
+			name:       "account of another operator",
+			path:       PathAccounts + "AOTHER",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "404 page not found\n",
+		},
+		{
+			name:       "account jwt secret not ready",
+			path:       PathAccounts + "ANOTREADY",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "404 page not found\n",
+		},
+		{
+			name:       "unknown account",
+			path:       PathAccounts + "AUNKNOWN",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "404 page not found\n",
+		},
+		{
+			name:       "nested path",
+			path:       PathAccounts + "AACC/jwt",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "404 page not found\n",
+		},
+		{
+			name:       "internal errors are not exposed",
+			path:       PathAccounts + "AMISSINGKEY",
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError) + "\n",
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			path:       PathAccounts + "AACC",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   http.StatusText(http.StatusMethodNotAllowed) + "\n",
+		},
+		{
+			name:       "invalid wait",
+			path:       PathAccounts + "AACC?wait=-1s",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "invalid wait \"-1s\", must be a positive duration\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, tt.path, nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			rec := httptest.NewRecorder()
+
+			if strings.HasPrefix(tt.path, PathAccounts) {
+				s.handleAccount(rec, req)
+			} else {
+				s.handleOperator(rec, req)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestServeJWTLongPoll(t *testing.T) {
+	etag := etagFor([]byte("acc.jwt"))
+
+	t.Run("returns when the jwt changes", func(t *testing.T) {
+		secret := newTestJWTSecret("acc-jwt", "acc.jwt")
+		s := newTestServer(newTestAccount("acc", "op", "AACC"), secret)
+
+		req := httptest.NewRequest(http.MethodGet, PathAccounts+"AACC?wait=10s", nil)
+		req.Header.Set("If-None-Match", etag)
+
+		rec := httptest.NewRecorder()
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			s.handleAccount(rec, req)
+		}()
+
+		// changes which do not affect the JWT keep the request waiting
+		s.notify()
+
+		select {
+		case <-done:
+			t.Fatalf("request returned with status %d before the jwt changed", rec.Code)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		secret.Data[v1alpha1.NatsSecretJWTKey] = []byte("updated.jwt")
+
+		if err := s.client.(publicKeyIndexClient).Update(context.Background(), secret); err != nil {
+			t.Fatal(err)
+		}
+
+		s.notify()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("request did not return after the jwt changed")
+		}
+
+		if rec.Code != http.StatusOK || rec.Body.String() != "updated.jwt" {
+			t.Errorf("response = %d %q, want 200 \"updated.jwt\"", rec.Code, rec.Body.String())
+		}
+
+		if got, want := rec.Header().Get("ETag"), etagFor([]byte("updated.jwt")); got != want {
+			t.Errorf("ETag = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("not modified once the wait has elapsed", func(t *testing.T) {
+		s := newTestServer(newTestAccount("acc", "op", "AACC"), newTestJWTSecret("acc-jwt", "acc.jwt"))
+
+		req := httptest.NewRequest(http.MethodGet, PathAccounts+"AACC?wait=50ms", nil)
+		req.Header.Set("If-None-Match", etag)
+
+		rec := httptest.NewRecorder()
+		start := time.Now()
+
+		s.handleAccount(rec, req)
+
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("request returned after %s, want it to wait", elapsed)
+		}
+
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusNotModified)
+		}
+
+		if got := rec.Header().Get("ETag"); got != etag {
+			t.Errorf("ETag = %s, want %s", got, etag)
+		}
+	})
+}
+
+func TestHandleHealth(t *testing.T) {
+	s := newTestServer()
+	s.synced = false
+
+	rec := httptest.NewRecorder()
+	s.handleHealth(rec, httptest.NewRequest(http.MethodGet, PathHealth, nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status before the cache synced = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	s.synced = true
+
+	rec = httptest.NewRecorder()
+	s.handleHealth(rec, httptest.NewRequest(http.MethodGet, PathHealth, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status after the cache synced = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestParseWait(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{query: "", want: 0},
+		{query: "wait=30s", want: 30 * time.Second},
+		{query: "wait=1h", want: MaxWait},
+		{query: "wait=-1s", wantErr: true},
+		{query: "wait=soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := parseWait(httptest.NewRequest(http.MethodGet, PathOperator+"?"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseWait() error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseWait() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -196,8 +196,9 @@ func (r conditionsImpl) SetCondition(cond Condition) {
 		if c.Type != t {
 			conditions = append(conditions, c)
 		} else {
-			// If we'd only update the LastTransitionTime, then return.
+			// If we'd only update the LastTransitionTime or ObservedGeneration, then return.
 			cond.LastTransitionTime = c.LastTransitionTime
+			cond.ObservedGeneration = c.ObservedGeneration
 			if reflect.DeepEqual(cond, c) {
 				return
 			}
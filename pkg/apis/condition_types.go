@@ -33,6 +33,16 @@ const (
 	// ConditionSucceeded specifies that the resource has finished.
 	// For resource which run to completion.
 	ConditionSucceeded ConditionType = "Succeeded"
+
+	// ConditionReconciling specifies that the controller is working towards
+	// the desired state of the resource, see
+	// https://github.com/kubernetes-sigs/cli-utils/tree/master/pkg/kstatus.
+	ConditionReconciling ConditionType = "Reconciling"
+
+	// ConditionStalled specifies that the controller cannot make progress
+	// until the resource or one of its dependencies is changed, see
+	// https://github.com/kubernetes-sigs/cli-utils/tree/master/pkg/kstatus.
+	ConditionStalled ConditionType = "Stalled"
 )
 
 // ConditionSeverity expresses the severity of a Condition Type failing.
@@ -82,6 +92,11 @@ type Condition struct {
 	// A human readable message indicating details about the transition.
 	// +optional
 	Message string `json:"message,omitempty" description:"human-readable message indicating details about last transition"`
+
+	// ObservedGeneration is the generation of the resource which the condition
+	// was last evaluated against.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" description:"generation of the resource which the condition was last evaluated against"`
 }
 
 // IsTrue is true if the condition is True
@@ -0,0 +1,39 @@
+package helpers
+
+import "github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+
+// PushTargets returns the targets which Account JWTs should be pushed to for the Operator. If the Operator does not
+// define any push targets a single target named v1alpha1.DefaultPushTarget is returned, which uses AccountServerURL
+// and TLSConfig from the Operator spec. No targets are returned if pushing has been disabled.
+func PushTargets(operator *v1alpha1.Operator) []v1alpha1.PushTarget {
+	if operator.Spec.DisableJWTPush {
+		return nil
+	}
+
+	if len(operator.Spec.PushTargets) > 0 {
+		return operator.Spec.PushTargets
+	}
+
+	return []v1alpha1.PushTarget{
+		{
+			Name:      v1alpha1.DefaultPushTarget,
+			URL:       operator.Spec.AccountServerURL,
+			TLSConfig: operator.Spec.TLSConfig,
+		},
+	}
+}
+
+// PushQuorum returns the number of push targets which must be updated for an Account JWT to be considered pushed,
+// given the Operator has targets push targets in total.
+func PushQuorum(operator *v1alpha1.Operator, targets int) int {
+	if operator.Spec.PushQuorum == nil {
+		return targets
+	}
+
+	quorum := int(*operator.Spec.PushQuorum)
+	if quorum < 1 || quorum > targets {
+		return targets
+	}
+
+	return quorum
+}
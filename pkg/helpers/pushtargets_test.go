@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+func TestPushQuorum(t *testing.T) {
+	quorum := func(n int32) *int32 { return &n }
+
+	tests := []struct {
+		name    string
+		quorum  *int32
+		targets int
+		want    int
+	}{
+		{name: "defaults to all targets", targets: 3, want: 3},
+		{name: "below the number of targets", quorum: quorum(2), targets: 3, want: 2},
+		{name: "equal to the number of targets", quorum: quorum(3), targets: 3, want: 3},
+		{name: "above the number of targets", quorum: quorum(4), targets: 3, want: 3},
+		{name: "zero", quorum: quorum(0), targets: 3, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := &v1alpha1.Operator{Spec: v1alpha1.OperatorSpec{PushQuorum: tt.quorum}}
+
+			if got := PushQuorum(operator, tt.targets); got != tt.want {
+				t.Errorf("PushQuorum() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPushTargets(t *testing.T) {
+	targets := []v1alpha1.PushTarget{{Name: "east", URL: "nats://east:4222"}, {Name: "west", URL: "nats://west:4222"}}
+
+	tests := []struct {
+		name string
+		spec v1alpha1.OperatorSpec
+		want []string
+	}{
+		{name: "default target", spec: v1alpha1.OperatorSpec{AccountServerURL: "nats://nats:4222"}, want: []string{v1alpha1.DefaultPushTarget}},
+		{name: "push targets", spec: v1alpha1.OperatorSpec{PushTargets: targets}, want: []string{"east", "west"}},
+		{name: "push disabled", spec: v1alpha1.OperatorSpec{PushTargets: targets, DisableJWTPush: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, target := range PushTargets(&v1alpha1.Operator{Spec: tt.spec}) {
+				got = append(got, target.Name)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("PushTargets() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
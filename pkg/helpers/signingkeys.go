@@ -8,34 +8,49 @@ import (
 // NextSigningKeys compares the current SigningKeys assigned to a resource to a list of SigningKeys which currently
 // exist on the cluster and returns the next list of SigningKeys to be assigned to the resource.
 //
+// A SigningKey which has been rotated contributes an entry for its current KeyPair and one for each of its retiring
+// KeyPairs, so that the owner continues to trust the previous keys during the rotation grace period. Entries are
+// therefore matched by public key rather than by name.
+//
 // This function attempts to preserve the order of SigningKeys on the resource, so that no-op updates do not trigger an
 // update on the status. New SigningKeys are appended to the end of the list, and SigningKeys which are no longer active
 // are removed from the list. Any changes to existing SigningKeys are kept at the same index (however removals may cause
 // indices to shift).
 func NextSigningKeys(ownerUID types.UID, current []v1alpha1.SigningKeyEmbeddedStatus, next *v1alpha1.SigningKeyList) []v1alpha1.SigningKeyEmbeddedStatus {
-	existingSKs := make(map[string]v1alpha1.SigningKeyEmbeddedStatus)
-	for _, sk := range current {
-		existingSKs[sk.Name] = sk
-	}
+	// create a map of SigningKeys by public key for easier lookup, keeping track of the order in which they were found
+	// so that new SigningKeys are appended deterministically
+	nextSKsByPublicKey := make(map[string]v1alpha1.SigningKeyEmbeddedStatus)
+
+	var nextPublicKeys []string
 
-	// create a map of SigningKeys by name for easier lookup, removing any we find which already exist on the status
-	nextSKsByName := make(map[string]v1alpha1.SigningKeyEmbeddedStatus)
 	for _, sk := range next.Items {
 		// this SigningKey is not ready or is owned by another account
 		if !sk.Status.IsReady() || sk.Status.OwnerRef.UID != ownerUID {
 			continue
 		}
 
-		nextSKsByName[sk.GetName()] = v1alpha1.SigningKeyEmbeddedStatus{
-			Name:    sk.GetName(),
-			KeyPair: *sk.Status.KeyPair,
+		keyPairs := make([]v1alpha1.KeyPair, 0, len(sk.Status.RetiringKeyPairs)+1)
+		keyPairs = append(keyPairs, *sk.Status.KeyPair)
+
+		for _, retiring := range sk.Status.RetiringKeyPairs {
+			keyPairs = append(keyPairs, retiring.KeyPair)
+		}
+
+		for _, kp := range keyPairs {
+			nextSKsByPublicKey[kp.PublicKey] = v1alpha1.SigningKeyEmbeddedStatus{
+				Name:    sk.GetName(),
+				KeyPair: kp,
+				Scope:   sk.Spec.Scope.DeepCopy(),
+			}
+
+			nextPublicKeys = append(nextPublicKeys, kp.PublicKey)
 		}
 	}
 
-	nextSKs := make([]v1alpha1.SigningKeyEmbeddedStatus, 0, len(nextSKsByName))
+	nextSKs := make([]v1alpha1.SigningKeyEmbeddedStatus, 0, len(nextSKsByPublicKey))
 
 	for _, existing := range current {
-		next, ok := nextSKsByName[existing.Name]
+		next, ok := nextSKsByPublicKey[existing.KeyPair.PublicKey]
 
 		if !ok {
 			// this SigningKey no longer active on this Account, so we don't need to add to nextSKs
@@ -45,14 +60,16 @@ func NextSigningKeys(ownerUID types.UID, current []v1alpha1.SigningKeyEmbeddedSt
 		// add the SigningKey to the nextSKs slice
 		nextSKs = append(nextSKs, next)
 
-		// remove the SigningKey from nextSKsByName so that we can check for any SigningKeys which need to be appended
-		// at the end
-		delete(nextSKsByName, existing.Name)
+		// remove the SigningKey from nextSKsByPublicKey so that we can check for any SigningKeys which need to be
+		// appended at the end
+		delete(nextSKsByPublicKey, existing.KeyPair.PublicKey)
 	}
 
-	// whatever remains in nextSKsByName are new SigningKeys we weren't previously aware of
-	for _, next := range nextSKsByName {
-		nextSKs = append(nextSKs, next)
+	// whatever remains in nextSKsByPublicKey are new SigningKeys we weren't previously aware of
+	for _, publicKey := range nextPublicKeys {
+		if next, ok := nextSKsByPublicKey[publicKey]; ok {
+			nextSKs = append(nextSKs, next)
+		}
 	}
 
 	return nextSKs
@@ -0,0 +1,137 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+const ownerUID types.UID = "owner-uid"
+
+// signingKey returns a ready SigningKey owned by owner with the given current and retiring public keys.
+func signingKey(name string, owner types.UID, publicKey string, retiring ...string) v1alpha1.SigningKey {
+	sk := v1alpha1.SigningKey{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+
+	sk.Status.InitializeConditions()
+	sk.Status.MarkOwnerResolved(v1alpha1.TypedObjectReference{UID: owner})
+	sk.Status.MarkSeedSecretReady(publicKey, name+"-seed")
+
+	for _, pk := range retiring {
+		sk.Status.RetiringKeyPairs = append(sk.Status.RetiringKeyPairs, v1alpha1.RetiringKeyPair{
+			KeyPair: v1alpha1.KeyPair{PublicKey: pk, SeedSecretName: name + "-" + pk},
+		})
+	}
+
+	return sk
+}
+
+// embedded returns the SigningKeyEmbeddedStatus expected for a keypair of the SigningKey with the given name.
+func embedded(name, publicKey, seedSecretName string) v1alpha1.SigningKeyEmbeddedStatus {
+	return v1alpha1.SigningKeyEmbeddedStatus{
+		Name: name,
+		KeyPair: v1alpha1.KeyPair{
+			PublicKey:      publicKey,
+			SeedSecretName: seedSecretName,
+		},
+	}
+}
+
+func TestNextSigningKeys(t *testing.T) {
+	notReady := signingKey("not-ready", ownerUID, "ANOTREADY")
+	notReady.Status.MarkSeedSecretUnknown("Pending", "")
+
+	tests := []struct {
+		name    string
+		current []v1alpha1.SigningKeyEmbeddedStatus
+		next    []v1alpha1.SigningKey
+		want    []v1alpha1.SigningKeyEmbeddedStatus
+	}{
+		{
+			name: "new signing keys are appended in order",
+			next: []v1alpha1.SigningKey{
+				signingKey("a", ownerUID, "AA"),
+				signingKey("b", ownerUID, "AB"),
+			},
+			want: []v1alpha1.SigningKeyEmbeddedStatus{
+				embedded("a", "AA", "a-seed"),
+				embedded("b", "AB", "b-seed"),
+			},
+		},
+		{
+			name: "existing order is preserved",
+			current: []v1alpha1.SigningKeyEmbeddedStatus{
+				embedded("b", "AB", "b-seed"),
+				embedded("a", "AA", "a-seed"),
+			},
+			next: []v1alpha1.SigningKey{
+				signingKey("a", ownerUID, "AA"),
+				signingKey("b", ownerUID, "AB"),
+				signingKey("c", ownerUID, "AC"),
+			},
+			want: []v1alpha1.SigningKeyEmbeddedStatus{
+				embedded("b", "AB", "b-seed"),
+				embedded("a", "AA", "a-seed"),
+				embedded("c", "AC", "c-seed"),
+			},
+		},
+		{
+			name: "signing keys which are not ready or owned by another resource are skipped",
+			current: []v1alpha1.SigningKeyEmbeddedStatus{
+				embedded("not-ready", "ANOTREADY", "not-ready-seed"),
+			},
+			next: []v1alpha1.SigningKey{
+				notReady,
+				signingKey("other", "other-uid", "AOTHER"),
+				signingKey("a", ownerUID, "AA"),
+			},
+			want: []v1alpha1.SigningKeyEmbeddedStatus{
+				embedded("a", "AA", "a-seed"),
+			},
+		},
+		{
+			name: "rotated signing key keeps its previous keypair at the same index",
+			current: []v1alpha1.SigningKeyEmbeddedStatus{
+				embedded("a", "AOLD", "a-seed"),
+				embedded("b", "AB", "b-seed"),
+			},
+			next: []v1alpha1.SigningKey{
+				signingKey("a", ownerUID, "ANEW", "AOLD"),
+				signingKey("b", ownerUID, "AB"),
+			},
+			want: []v1alpha1.SigningKeyEmbeddedStatus{
+				embedded("a", "AOLD", "a-AOLD"),
+				embedded("b", "AB", "b-seed"),
+				embedded("a", "ANEW", "a-seed"),
+			},
+		},
+		{
+			name: "retired keypair is removed",
+			current: []v1alpha1.SigningKeyEmbeddedStatus{
+				embedded("a", "AOLD", "a-AOLD"),
+				embedded("a", "ANEW", "a-seed"),
+			},
+			next: []v1alpha1.SigningKey{
+				signingKey("a", ownerUID, "ANEW"),
+			},
+			want: []v1alpha1.SigningKeyEmbeddedStatus{
+				embedded("a", "ANEW", "a-seed"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NextSigningKeys(ownerUID, tt.current, &v1alpha1.SigningKeyList{Items: tt.next})
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("NextSigningKeys() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package nsc
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
+
 	"github.com/nats-io/jwt/v2"
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nkeys"
@@ -14,29 +18,41 @@ import (
 const (
 	RequestSubjectClaimsUpdate = "$SYS.REQ.CLAIMS.UPDATE"
 	RequestSubjectClaimsDelete = "$SYS.REQ.CLAIMS.DELETE"
+	RequestSubjectClaimsList   = "$SYS.REQ.CLAIMS.LIST"
+
+	// RequestSubjectClaimsLookupFormat is formatted with the public key of the account to look up.
+	RequestSubjectClaimsLookupFormat = "$SYS.REQ.ACCOUNT.%s.CLAIMS.LOOKUP"
 )
 
-type Client struct {
-	conn *nats.Conn
+// DefaultRequestTimeout bounds each request made by a Client. Pooled connections reconnect indefinitely, so without it
+// a request to a server which is unreachable or never replies would block until the caller's context is done.
+const DefaultRequestTimeout = 10 * time.Second
 
-	operator        nkeys.KeyPair
-	operatorSubject string
+type Client struct {
+	conn    *nats.Conn
+	timeout time.Duration
 }
 
-func Connect(url string, operator nkeys.KeyPair, systemAccountSeed []byte, opts ...nats.Option) (*Client, error) {
-	ujwt, useed, err := makeTemporaryUser(systemAccountSeed)
+// Connect connects to the NATS servers at url, authenticating with the system user credentials in creds.
+func Connect(url string, creds []byte, opts ...nats.Option) (*Client, error) {
+	ujwt, err := jwt.ParseDecoratedJWT(creds)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temporary system account user: %w", err)
+		return nil, fmt.Errorf("failed to parse system user JWT from credentials: %w", err)
 	}
 
-	operatorPubkey, err := operator.PublicKey()
+	userKP, err := jwt.ParseDecoratedUserNKey(creds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse system user seed from credentials: %w", err)
+	}
+
+	useed, err := userKP.Seed()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get operator public key: %w", err)
+		return nil, fmt.Errorf("failed to get system user seed: %w", err)
 	}
 
 	options := append(make([]nats.Option, 0, len(opts)+2), opts...)
 	options = append(options,
-		nats.UserJWTAndSeed(ujwt, useed),
+		nats.UserJWTAndSeed(ujwt, string(useed)),
 		nats.Name("nats-account-operator"),
 	)
 
@@ -46,9 +62,8 @@ func Connect(url string, operator nkeys.KeyPair, systemAccountSeed []byte, opts
 	}
 
 	return &Client{
-		conn:            conn,
-		operator:        operator,
-		operatorSubject: operatorPubkey,
+		conn:    conn,
+		timeout: DefaultRequestTimeout,
 	}, nil
 }
 
@@ -67,11 +82,17 @@ func (c *Client) Push(ctx context.Context, jwt string) error {
 	return nil
 }
 
-func (c *Client) Delete(ctx context.Context, subject string) error {
-	claims := jwt.NewGenericClaims(c.operatorSubject)
+// Delete requests that the account JWT for subject is deleted, the request must be signed by the Operator.
+func (c *Client) Delete(ctx context.Context, operator nkeys.KeyPair, subject string) error {
+	operatorPubkey, err := operator.PublicKey()
+	if err != nil {
+		return fmt.Errorf("failed to get operator public key: %w", err)
+	}
+
+	claims := jwt.NewGenericClaims(operatorPubkey)
 	claims.Data["accounts"] = []string{subject}
 
-	payload, err := claims.Encode(c.operator)
+	payload, err := claims.Encode(operator)
 	if err != nil {
 		return fmt.Errorf("failed to encode jwt with operator key pair: %w", err)
 	}
@@ -90,52 +111,86 @@ func (c *Client) Delete(ctx context.Context, subject string) error {
 	return nil
 }
 
-func (c *Client) do(ctx context.Context, subj string, data []byte) (*internal.UpdateResponse, error) {
-	resp, err := c.conn.RequestWithContext(ctx, subj, data)
+// Lookup returns the account JWT currently held by the server's resolver for the account with the given public key,
+// an empty string is returned if the resolver does not have a JWT for the account.
+func (c *Client) Lookup(ctx context.Context, account string) (string, error) {
+	resp, err := c.request(ctx, fmt.Sprintf(RequestSubjectClaimsLookupFormat, account), nil)
+	if err != nil {
+		return "", err
+	}
+
+	data := bytes.TrimSpace(resp.Data)
+
+	// the resolver replies with the raw JWT, or an empty message if not found, errors are the same JSON response as
+	// returned for updates
+	if len(data) > 0 && data[0] == '{' {
+		var reply internal.UpdateResponse
+		if err := json.Unmarshal(data, &reply); err != nil {
+			return "", fmt.Errorf("failed to json unmarshal response: %w", err)
+		}
+
+		if reply.Error != nil {
+			return "", fmt.Errorf("nats lookup failed: %s", reply.Error.Description)
+		}
+
+		return "", fmt.Errorf("nats lookup failed: unexpected response: %s", reply.Data.Message)
+	}
+
+	return string(data), nil
+}
+
+// List returns the public keys of all accounts held by the server's resolver.
+func (c *Client) List(ctx context.Context) ([]string, error) {
+	resp, err := c.request(ctx, RequestSubjectClaimsList, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var reply internal.UpdateResponse
+	var reply internal.ListResponse
 	if err := json.Unmarshal(resp.Data, &reply); err != nil {
 		return nil, fmt.Errorf("failed to json unmarshal response: %w", err)
 	}
 
-	return &reply, nil
-}
+	if reply.Error != nil {
+		return nil, fmt.Errorf("nats list failed: %s", reply.Error.Description)
+	}
 
-func (c *Client) Close() {
-	c.conn.Close()
+	return reply.Data, nil
 }
 
-func makeTemporaryUser(accountSeed []byte) (ujwt string, seed string, err error) {
-	sysKP, err := nkeys.FromSeed(accountSeed)
+func (c *Client) do(ctx context.Context, subj string, data []byte) (*internal.UpdateResponse, error) {
+	resp, err := c.request(ctx, subj, data)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	userKP, err := nkeys.CreateUser()
-	if err != nil {
-		return "", "", err
+	var reply internal.UpdateResponse
+	if err := json.Unmarshal(resp.Data, &reply); err != nil {
+		return nil, fmt.Errorf("failed to json unmarshal response: %w", err)
 	}
 
-	userPubkey, err := userKP.PublicKey()
-	if err != nil {
-		return "", "", err
-	}
+	return &reply, nil
+}
 
-	userClaims := jwt.NewUserClaims(userPubkey)
-	userClaims.Name = "k8s-operator-tmp-user"
+// request sends a request to subj and waits for the reply for at most the request timeout. An error wrapping
+// ErrRequestTimeout is returned if there is no reply in time, unless ctx was done first.
+func (c *Client) request(ctx context.Context, subj string, data []byte) (*nats.Msg, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
 
-	ujwt, err = userClaims.Encode(sysKP)
-	if err != nil {
-		return "", "", err
+	msg, err := c.conn.RequestWithContext(reqCtx, subj, data)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, fmt.Errorf("%w: no reply to %s within %s", ErrRequestTimeout, subj, c.timeout)
 	}
 
-	userSeed, err := userKP.Seed()
-	if err != nil {
-		return "", "", err
-	}
+	return msg, err
+}
+
+// IsConnected returns true if the underlying connection is currently connected.
+func (c *Client) IsConnected() bool {
+	return c.conn.IsConnected()
+}
 
-	return ujwt, string(userSeed), nil
+func (c *Client) Close() {
+	c.conn.Close()
 }
@@ -0,0 +1,200 @@
+package nsc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nkeys"
+
+	"github.com/versori-oss/nats-account-operator/pkg/nsc/nsctest"
+)
+
+// newTestClient connects to srv with a short request timeout.
+func newTestClient(t *testing.T, srv *nsctest.Server) *Client {
+	t.Helper()
+
+	var dials atomic.Int32
+
+	client, err := connectFunc(srv, &dials)()
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	client.timeout = 100 * time.Millisecond
+
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func TestClientPush(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler nsctest.HandlerFunc
+		wantErr string
+	}{
+		{
+			name:    "success",
+			handler: func([]byte) []byte { return []byte(`{"data":{"code":200,"message":"jwt updated"}}`) },
+		},
+		{
+			name:    "error response",
+			handler: func([]byte) []byte { return []byte(`{"error":{"code":500,"description":"jwt validation failed"}}`) },
+			wantErr: "nats push failed: jwt validation failed",
+		},
+		{
+			name:    "no reply",
+			handler: func([]byte) []byte { return nil },
+			wantErr: ErrRequestTimeout.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := nsctest.NewServer(t)
+			srv.Handle(RequestSubjectClaimsUpdate, tt.handler)
+
+			err := newTestClient(t, srv).Push(context.Background(), "jwt")
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Push() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Push() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestClientRequestTimeout checks that requests do not block while the connection is reconnecting, when the reconcile
+// context has no deadline.
+func TestClientRequestTimeout(t *testing.T) {
+	srv := nsctest.NewServer(t)
+	client := newTestClient(t, srv)
+
+	operator, err := nkeys.CreateOperator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Close()
+
+	eventually(t, func() bool { return !client.IsConnected() }, "client did not disconnect")
+
+	start := time.Now()
+
+	err = client.Delete(context.Background(), operator, "ACCOUNT")
+	if !errors.Is(err, ErrRequestTimeout) {
+		t.Errorf("Delete() error = %v, want %v", err, ErrRequestTimeout)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Delete() took %s, want it bounded by the request timeout", elapsed)
+	}
+
+	// cancellation by the caller is reported as is rather than as a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := client.Push(ctx, "jwt"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Push() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestClientLookup(t *testing.T) {
+	const account = "ACCOUNT"
+
+	tests := []struct {
+		name    string
+		handler nsctest.HandlerFunc
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "found",
+			handler: func([]byte) []byte { return []byte("account.jwt\n") },
+			want:    "account.jwt",
+		},
+		{
+			name:    "not found",
+			handler: func([]byte) []byte { return []byte{} },
+			want:    "",
+		},
+		{
+			name:    "error response",
+			handler: func([]byte) []byte { return []byte(`{"error":{"code":500,"description":"fetch failed"}}`) },
+			wantErr: "nats lookup failed: fetch failed",
+		},
+		{
+			name:    "no reply",
+			handler: func([]byte) []byte { return nil },
+			wantErr: ErrRequestTimeout.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := nsctest.NewServer(t)
+			srv.Handle(fmt.Sprintf(RequestSubjectClaimsLookupFormat, account), tt.handler)
+
+			got, err := newTestClient(t, srv).Lookup(context.Background(), account)
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Lookup() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Lookup() error = %v, want %q", err, tt.wantErr)
+			case got != tt.want:
+				t.Errorf("Lookup() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientList(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler nsctest.HandlerFunc
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "accounts",
+			handler: func([]byte) []byte { return []byte(`{"data":["A1","A2"]}`) },
+			want:    []string{"A1", "A2"},
+		},
+		{
+			name:    "error response",
+			handler: func([]byte) []byte { return []byte(`{"error":{"code":500,"description":"list failed"}}`) },
+			wantErr: "nats list failed: list failed",
+		},
+		{
+			name:    "no reply",
+			handler: func([]byte) []byte { return nil },
+			wantErr: ErrRequestTimeout.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := nsctest.NewServer(t)
+			srv.Handle(RequestSubjectClaimsList, tt.handler)
+
+			got, err := newTestClient(t, srv).List(context.Background())
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("List() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("List() error = %v, want %q", err, tt.wantErr)
+			case strings.Join(got, ",") != strings.Join(tt.want, ","):
+				t.Errorf("List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
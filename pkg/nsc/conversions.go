@@ -1,6 +1,8 @@
 package nsc
 
 import (
+	"fmt"
+
 	"github.com/nats-io/jwt/v2"
 	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
 )
@@ -115,3 +117,131 @@ func ConvertToNatsTimeRanges(in []v1alpha1.StartEndTime) []jwt.TimeRange {
 
 	return out
 }
+
+func ConvertToNATSPermissions(in *v1alpha1.UserPermissions) jwt.Permissions {
+	if in == nil {
+		return jwt.Permissions{}
+	}
+
+	out := jwt.Permissions{
+		Pub: jwt.Permission{
+			Allow: in.Pub.Allow,
+			Deny:  in.Pub.Deny,
+		},
+		Sub: jwt.Permission{
+			Allow: in.Sub.Allow,
+			Deny:  in.Sub.Deny,
+		},
+	}
+
+	if in.Resp != nil {
+		out.Resp = &jwt.ResponsePermission{
+			MaxMsgs: in.Resp.MaxMsgs,
+			Expires: in.Resp.TTL.Duration,
+		}
+	}
+
+	return out
+}
+
+func ConvertToNATSUserLimits(in v1alpha1.UserLimits, defaults jwt.Limits) jwt.Limits {
+	return jwt.Limits{
+		UserLimits: jwt.UserLimits{
+			Src:    in.Src,
+			Times:  ConvertToNatsTimeRanges(in.Times),
+			Locale: in.Locale,
+		},
+		NatsLimits: ConvertToNatsLimits(in.NatsLimits, defaults.NatsLimits),
+	}
+}
+
+func ConvertToNATSUserPermissionLimits(in v1alpha1.UserPermissionLimits, defaults jwt.UserPermissionLimits) jwt.UserPermissionLimits {
+	return jwt.UserPermissionLimits{
+		Permissions:            ConvertToNATSPermissions(in.Permissions),
+		Limits:                 ConvertToNATSUserLimits(in.Limits, defaults.Limits),
+		BearerToken:            getDefaultFromPtr(in.BearerToken, defaults.BearerToken),
+		AllowedConnectionTypes: in.AllowedConnectionTypes,
+	}
+}
+
+func ConvertToNATSJetStreamLimits(in v1alpha1.JetStreamLimits) jwt.JetStreamLimits {
+	return jwt.JetStreamLimits{
+		MemoryStorage:        in.MemoryStorage,
+		DiskStorage:          in.DiskStorage,
+		Streams:              in.Streams,
+		Consumer:             in.Consumer,
+		MaxAckPending:        in.MaxAckPending,
+		MemoryMaxStreamBytes: in.MemoryMaxStreamBytes,
+		DiskMaxStreamBytes:   in.DiskMaxStreamBytes,
+		MaxBytesRequired:     in.MaxBytesRequired,
+	}
+}
+
+func ConvertToNATSJetStreamTieredLimits(in map[string]v1alpha1.JetStreamLimits) jwt.JetStreamTieredLimits {
+	if len(in) == 0 {
+		return jwt.JetStreamTieredLimits{}
+	}
+
+	out := make(jwt.JetStreamTieredLimits, len(in))
+	for tier, limits := range in {
+		out[tier] = ConvertToNATSJetStreamLimits(limits)
+	}
+
+	return out
+}
+
+func ConvertToNATSMappings(in map[string][]v1alpha1.WeightedMapping) jwt.Mapping {
+	out := jwt.Mapping{}
+
+	for source, destinations := range in {
+		mappings := make([]jwt.WeightedMapping, len(destinations))
+		for i, v := range destinations {
+			mappings[i] = jwt.WeightedMapping{
+				Subject: jwt.Subject(v.Subject),
+				Weight:  v.Weight,
+				Cluster: v.Cluster,
+			}
+		}
+
+		out[jwt.Subject(source)] = mappings
+	}
+
+	return out
+}
+
+// ValidateMappings checks that the weights of the destinations for each source subject do not add up to more than
+// 100, destinations without a weight count as 100.
+func ValidateMappings(in map[string][]v1alpha1.WeightedMapping) error {
+	for source, destinations := range in {
+		total := 0
+
+		for _, v := range destinations {
+			if v.Weight == 0 {
+				total += 100
+			} else {
+				total += int(v.Weight)
+			}
+		}
+
+		if total > 100 {
+			return fmt.Errorf("%w: weights of mappings for subject %q add up to %d, must be at most 100", ErrInvalidSpec, source, total)
+		}
+	}
+
+	return nil
+}
+
+func ConvertToNATSExternalAuthorization(in *v1alpha1.AccountAuthorizationStatus) jwt.ExternalAuthorization {
+	if in == nil {
+		return jwt.ExternalAuthorization{}
+	}
+
+	out := jwt.ExternalAuthorization{
+		XKey: in.XKey,
+	}
+
+	out.AuthUsers.Add(in.AuthUsers...)
+	out.AllowedAccounts.Add(in.AllowedAccounts...)
+
+	return out
+}
@@ -0,0 +1,115 @@
+package nsc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nats-io/jwt/v2"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+func TestValidateMappings(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings map[string][]v1alpha1.WeightedMapping
+		wantErr  bool
+	}{
+		{
+			name: "no mappings",
+		},
+		{
+			name: "weights add up to 100",
+			mappings: map[string][]v1alpha1.WeightedMapping{
+				"orders": {{Subject: "orders.v1", Weight: 60}, {Subject: "orders.v2", Weight: 40}},
+			},
+		},
+		{
+			name: "weights add up to 101",
+			mappings: map[string][]v1alpha1.WeightedMapping{
+				"orders": {{Subject: "orders.v1", Weight: 60}, {Subject: "orders.v2", Weight: 41}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero weight counts as 100",
+			mappings: map[string][]v1alpha1.WeightedMapping{
+				"orders": {{Subject: "orders.v1"}},
+			},
+		},
+		{
+			name: "zero weight alongside another destination",
+			mappings: map[string][]v1alpha1.WeightedMapping{
+				"orders": {{Subject: "orders.v1"}, {Subject: "orders.v2", Weight: 1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "weights are checked per source subject",
+			mappings: map[string][]v1alpha1.WeightedMapping{
+				"orders":   {{Subject: "orders.v1", Weight: 100}},
+				"payments": {{Subject: "payments.v1", Weight: 50}, {Subject: "payments.v2", Weight: 50}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMappings(tt.mappings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateMappings() error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, ErrInvalidSpec) {
+				t.Errorf("ValidateMappings() error = %v, want ErrInvalidSpec", err)
+			}
+		})
+	}
+}
+
+func TestConvertToNATSMappings(t *testing.T) {
+	in := map[string][]v1alpha1.WeightedMapping{
+		"orders": {
+			{Subject: "orders.east", Weight: 80, Cluster: "east"},
+			{Subject: "orders.west", Weight: 20, Cluster: "west"},
+		},
+		"payments": {{Subject: "payments.v1"}},
+	}
+
+	want := jwt.Mapping{
+		"orders": {
+			{Subject: "orders.east", Weight: 80, Cluster: "east"},
+			{Subject: "orders.west", Weight: 20, Cluster: "west"},
+		},
+		"payments": {{Subject: "payments.v1"}},
+	}
+
+	if diff := cmp.Diff(want, ConvertToNATSMappings(in)); diff != "" {
+		t.Errorf("ConvertToNATSMappings() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCreateAccountClaimsMappings(t *testing.T) {
+	acc, operatorKP := newTestAccount(t, v1alpha1.AccountSpec{
+		Mappings: map[string][]v1alpha1.WeightedMapping{
+			"orders": {{Subject: "orders.east", Weight: 100, Cluster: "east"}},
+		},
+	})
+
+	_, ajwt, err := CreateAccountClaims(acc, operatorKP)
+	if err != nil {
+		t.Fatalf("CreateAccountClaims() error = %v", err)
+	}
+
+	claims, err := jwt.DecodeAccountClaims(ajwt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := jwt.Mapping{"orders": {{Subject: "orders.east", Weight: 100, Cluster: "east"}}}
+
+	if diff := cmp.Diff(want, claims.Mappings); diff != "" {
+		t.Errorf("Mappings mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -8,7 +8,27 @@ import (
 )
 
 func CreateAccountClaims(resource *v1alpha1.Account, signingKey nkeys.KeyPair) (claims *jwt.AccountClaims, ajwt string, err error) {
-	claims = jwt.NewAccountClaims(resource.Status.KeyPair.PublicKey)
+	claims, err = newAccountClaims(resource)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err = ValidateClaims(claims); err != nil {
+		return nil, "", err
+	}
+
+	ajwt, err = claims.Encode(signingKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode account claims: %w", err)
+	}
+
+	return claims, ajwt, nil
+}
+
+// newAccountClaims converts the Account spec, and the keys in its status, into Account claims without validating or
+// signing them.
+func newAccountClaims(resource *v1alpha1.Account) (*jwt.AccountClaims, error) {
+	claims := jwt.NewAccountClaims(resource.Status.KeyPair.PublicKey)
 	claims.Name = resource.Name
 
 	spec := resource.Spec
@@ -16,31 +36,62 @@ func CreateAccountClaims(resource *v1alpha1.Account, signingKey nkeys.KeyPair) (
 	claims.Exports = ConvertToNATSExports(spec.Exports)
 	claims.Imports = ConvertToNATSImports(spec.Imports)
 
+	if err := ValidateMappings(spec.Mappings); err != nil {
+		return nil, err
+	}
+
+	claims.Mappings = ConvertToNATSMappings(spec.Mappings)
+	claims.Authorization = ConvertToNATSExternalAuthorization(resource.Status.Authorization)
+
 	if spec.Limits != nil {
+		if err := ValidateOperatorLimits(spec.Limits); err != nil {
+			return nil, err
+		}
+
 		claims.Limits = jwt.OperatorLimits{
-			NatsLimits:    ConvertToNatsLimits(spec.Limits.Nats, claims.Limits.NatsLimits),
-			AccountLimits: ConvertToAccountLimits(spec.Limits.Account, claims.Limits.AccountLimits),
-			JetStreamLimits: jwt.JetStreamLimits{
-				MemoryStorage:        spec.Limits.JetStream.MemoryStorage,
-				DiskStorage:          spec.Limits.JetStream.DiskStorage,
-				Streams:              spec.Limits.JetStream.Streams,
-				Consumer:             spec.Limits.JetStream.Consumer,
-				MaxAckPending:        spec.Limits.JetStream.MaxAckPending,
-				MemoryMaxStreamBytes: spec.Limits.JetStream.MemoryMaxStreamBytes,
-				DiskMaxStreamBytes:   spec.Limits.JetStream.DiskMaxStreamBytes,
-				MaxBytesRequired:     spec.Limits.JetStream.MaxBytesRequired,
-			},
+			NatsLimits:            ConvertToNatsLimits(spec.Limits.Nats, claims.Limits.NatsLimits),
+			AccountLimits:         ConvertToAccountLimits(spec.Limits.Account, claims.Limits.AccountLimits),
+			JetStreamLimits:       ConvertToNATSJetStreamLimits(spec.Limits.JetStream),
+			JetStreamTieredLimits: ConvertToNATSJetStreamTieredLimits(spec.Limits.TieredLimits),
 		}
 	}
 
 	for _, sk := range resource.Status.SigningKeys {
-		claims.SigningKeys.Add(sk.KeyPair.PublicKey)
+		if sk.Scope == nil {
+			claims.SigningKeys.Add(sk.KeyPair.PublicKey)
+
+			continue
+		}
+
+		scope := jwt.NewUserScope()
+		scope.Key = sk.KeyPair.PublicKey
+		scope.Role = sk.Scope.Role
+		scope.Template = ConvertToNATSUserPermissionLimits(sk.Scope.Template, scope.Template)
+
+		claims.SigningKeys.AddScopedSigner(scope)
 	}
 
-	ajwt, err = claims.Encode(signingKey)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to encode account claims: %w", err)
+	for _, revocation := range resource.Status.Revocations {
+		claims.RevokeAt(revocation.PublicKey, revocation.RevokedAt.Time)
 	}
 
-	return claims, ajwt, nil
+	return claims, nil
+}
+
+// ValidateOperatorLimits checks that the limits can be represented in an Account JWT. Flat JetStream limits and tiered
+// JetStream limits are mutually exclusive.
+func ValidateOperatorLimits(limits *v1alpha1.OperatorLimits) error {
+	if len(limits.TieredLimits) == 0 {
+		return nil
+	}
+
+	if limits.JetStream != (v1alpha1.JetStreamLimits{}) {
+		return fmt.Errorf("%w: limits.jetStream and limits.tieredLimits are mutually exclusive", ErrInvalidSpec)
+	}
+
+	if _, ok := limits.TieredLimits[""]; ok {
+		return fmt.Errorf("%w: limits.tieredLimits must not contain a blank tier name", ErrInvalidSpec)
+	}
+
+	return nil
 }
@@ -0,0 +1,212 @@
+package nsc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// newTestKeyPair returns a new keypair created by create along with its public key.
+func newTestKeyPair(t *testing.T, create func() (nkeys.KeyPair, error)) (nkeys.KeyPair, string) {
+	t.Helper()
+
+	kp, err := create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := kp.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return kp, publicKey
+}
+
+// newTestAccount returns an Account with a new identity key and spec applied, along with the Operator key which
+// should issue it.
+func newTestAccount(t *testing.T, spec v1alpha1.AccountSpec) (*v1alpha1.Account, nkeys.KeyPair) {
+	t.Helper()
+
+	operatorKP, _ := newTestKeyPair(t, nkeys.CreateOperator)
+	_, accountPublicKey := newTestKeyPair(t, nkeys.CreateAccount)
+
+	acc := &v1alpha1.Account{Spec: spec}
+	acc.Name = "acc"
+	acc.Status.KeyPair = &v1alpha1.KeyPair{PublicKey: accountPublicKey}
+
+	return acc, operatorKP
+}
+
+func TestCreateAccountClaimsScopedSigningKeys(t *testing.T) {
+	_, unscopedKey := newTestKeyPair(t, nkeys.CreateAccount)
+	_, scopedKey := newTestKeyPair(t, nkeys.CreateAccount)
+
+	acc, operatorKP := newTestAccount(t, v1alpha1.AccountSpec{})
+	acc.Status.SigningKeys = []v1alpha1.SigningKeyEmbeddedStatus{
+		{Name: "unscoped", KeyPair: v1alpha1.KeyPair{PublicKey: unscopedKey}},
+		{
+			Name:    "scoped",
+			KeyPair: v1alpha1.KeyPair{PublicKey: scopedKey},
+			Scope: &v1alpha1.SigningKeyScope{
+				Role: "reader",
+				Template: v1alpha1.UserPermissionLimits{
+					Permissions: &v1alpha1.UserPermissions{Sub: v1alpha1.Permission{Allow: []string{"orders.>"}}},
+				},
+			},
+		},
+	}
+
+	_, ajwt, err := CreateAccountClaims(acc, operatorKP)
+	if err != nil {
+		t.Fatalf("CreateAccountClaims() error = %v", err)
+	}
+
+	claims, err := jwt.DecodeAccountClaims(ajwt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if scope, ok := claims.SigningKeys.GetScope(unscopedKey); !ok || scope != nil {
+		t.Errorf("unscoped signing key scope = %v, %t, want an unscoped signing key", scope, ok)
+	}
+
+	scope, ok := claims.SigningKeys.GetScope(scopedKey)
+	if !ok {
+		t.Fatalf("scoped signing key %s missing from the account claims", scopedKey)
+	}
+
+	userScope, ok := scope.(*jwt.UserScope)
+	if !ok {
+		t.Fatalf("scoped signing key scope = %T, want a *jwt.UserScope", scope)
+	}
+
+	if userScope.Key != scopedKey || userScope.Role != "reader" {
+		t.Errorf("user scope key, role = %s, %s, want %s, reader", userScope.Key, userScope.Role, scopedKey)
+	}
+
+	if diff := cmp.Diff(jwt.StringList{"orders.>"}, userScope.Template.Sub.Allow); diff != "" {
+		t.Errorf("user scope template sub allow mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateOperatorLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  v1alpha1.OperatorLimits
+		wantErr bool
+	}{
+		{
+			name: "no limits",
+		},
+		{
+			name:   "flat limits",
+			limits: v1alpha1.OperatorLimits{JetStream: v1alpha1.JetStreamLimits{DiskStorage: 1024}},
+		},
+		{
+			name: "tiered limits",
+			limits: v1alpha1.OperatorLimits{TieredLimits: map[string]v1alpha1.JetStreamLimits{
+				"R1": {DiskStorage: 1024},
+				"R3": {DiskStorage: 4096},
+			}},
+		},
+		{
+			name: "flat and tiered limits",
+			limits: v1alpha1.OperatorLimits{
+				JetStream:    v1alpha1.JetStreamLimits{DiskStorage: 1024},
+				TieredLimits: map[string]v1alpha1.JetStreamLimits{"R1": {DiskStorage: 1024}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "blank tier name",
+			limits: v1alpha1.OperatorLimits{TieredLimits: map[string]v1alpha1.JetStreamLimits{
+				"":   {DiskStorage: 1024},
+				"R3": {DiskStorage: 4096},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOperatorLimits(&tt.limits)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateOperatorLimits() error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, ErrInvalidSpec) {
+				t.Errorf("ValidateOperatorLimits() error = %v, want ErrInvalidSpec", err)
+			}
+		})
+	}
+}
+
+func TestConvertToNATSJetStreamTieredLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		in   map[string]v1alpha1.JetStreamLimits
+		want jwt.JetStreamTieredLimits
+	}{
+		{
+			name: "nil",
+			want: jwt.JetStreamTieredLimits{},
+		},
+		{
+			name: "tiers",
+			in: map[string]v1alpha1.JetStreamLimits{
+				"R1": {DiskStorage: 1024, Streams: 10},
+				"R3": {MemoryStorage: 2048, Consumer: 5, MaxBytesRequired: true},
+			},
+			want: jwt.JetStreamTieredLimits{
+				"R1": {DiskStorage: 1024, Streams: 10},
+				"R3": {MemoryStorage: 2048, Consumer: 5, MaxBytesRequired: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, ConvertToNATSJetStreamTieredLimits(tt.in)); diff != "" {
+				t.Errorf("ConvertToNATSJetStreamTieredLimits() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreateAccountClaimsTieredLimits(t *testing.T) {
+	acc, operatorKP := newTestAccount(t, v1alpha1.AccountSpec{
+		Limits: &v1alpha1.OperatorLimits{TieredLimits: map[string]v1alpha1.JetStreamLimits{
+			"R1": {DiskStorage: 1024, Streams: -1},
+			"R3": {DiskStorage: 4096, Streams: -1},
+		}},
+	})
+
+	_, ajwt, err := CreateAccountClaims(acc, operatorKP)
+	if err != nil {
+		t.Fatalf("CreateAccountClaims() error = %v", err)
+	}
+
+	claims, err := jwt.DecodeAccountClaims(ajwt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := jwt.JetStreamTieredLimits{
+		"R1": {DiskStorage: 1024, Streams: -1},
+		"R3": {DiskStorage: 4096, Streams: -1},
+	}
+
+	if diff := cmp.Diff(want, claims.Limits.JetStreamTieredLimits); diff != "" {
+		t.Errorf("JetStreamTieredLimits mismatch (-want +got):\n%s", diff)
+	}
+
+	if claims.Limits.JetStreamLimits != (jwt.JetStreamLimits{}) {
+		t.Errorf("JetStreamLimits = %+v, want no flat limits alongside tiered limits", claims.Limits.JetStreamLimits)
+	}
+}
@@ -0,0 +1,95 @@
+package nsc
+
+import (
+	"fmt"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// CreateOperatorClaims builds the self-signed Operator JWT from the full Operator spec, the signing keys in its status
+// and the public key of the resolved system account.
+func CreateOperatorClaims(resource *v1alpha1.Operator, systemAccount string, signingKey nkeys.KeyPair) (claims *jwt.OperatorClaims, ojwt string, err error) {
+	claims, err = newOperatorClaims(resource, systemAccount)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err = ValidateClaims(claims); err != nil {
+		return nil, "", err
+	}
+
+	ojwt, err = claims.Encode(signingKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode operator claims: %w", err)
+	}
+
+	return claims, ojwt, nil
+}
+
+// newOperatorClaims converts the Operator spec, and the keys in its status, into Operator claims without validating or
+// signing them.
+func newOperatorClaims(resource *v1alpha1.Operator, systemAccount string) (*jwt.OperatorClaims, error) {
+	claims := jwt.NewOperatorClaims(resource.Status.KeyPair.PublicKey)
+	claims.Name = resource.Name
+
+	spec := resource.Spec
+
+	if _, _, _, err := jwt.ParseServerVersion(spec.AssertServerVersion); err != nil {
+		return nil, fmt.Errorf("%w: assertServerVersion: %s", ErrInvalidSpec, err.Error())
+	}
+
+	claims.AccountServerURL = spec.AccountServerURL
+	claims.OperatorServiceURLs.Add(spec.OperatorServiceURLs...)
+	claims.SystemAccount = systemAccount
+	claims.AssertServerVersion = spec.AssertServerVersion
+	claims.StrictSigningKeyUsage = spec.StrictSigningKeyUsage
+	claims.Tags.Add(spec.Tags...)
+
+	for _, sk := range resource.Status.SigningKeys {
+		claims.SigningKeys.Add(sk.KeyPair.PublicKey)
+	}
+
+	return claims, nil
+}
+
+// OperatorClaimsChanges returns the names of the Operator spec fields which differ between got and want, it is used to
+// describe updates to the Operator JWT.
+func OperatorClaimsChanges(got, want *jwt.OperatorClaims) []string {
+	var changes []string
+
+	if got.Name != want.Name {
+		changes = append(changes, "name")
+	}
+
+	if !Equality.DeepEqual(got.SigningKeys, want.SigningKeys) {
+		changes = append(changes, "signingKeys")
+	}
+
+	if got.AccountServerURL != want.AccountServerURL {
+		changes = append(changes, "accountServerURL")
+	}
+
+	if !Equality.DeepEqual(got.OperatorServiceURLs, want.OperatorServiceURLs) {
+		changes = append(changes, "operatorServiceURLs")
+	}
+
+	if got.SystemAccount != want.SystemAccount {
+		changes = append(changes, "systemAccount")
+	}
+
+	if got.AssertServerVersion != want.AssertServerVersion {
+		changes = append(changes, "assertServerVersion")
+	}
+
+	if got.StrictSigningKeyUsage != want.StrictSigningKeyUsage {
+		changes = append(changes, "strictSigningKeyUsage")
+	}
+
+	if !Equality.DeepEqual(got.Tags, want.Tags) {
+		changes = append(changes, "tags")
+	}
+
+	return changes
+}
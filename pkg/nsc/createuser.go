@@ -2,54 +2,57 @@ package nsc
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/nats-io/jwt/v2"
 	"github.com/nats-io/nkeys"
 	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
 )
 
-func CreateUserClaims(resource *v1alpha1.User, signingKey nkeys.KeyPair) (claims *jwt.UserClaims, ujwt string, err error) {
+// UserClaimsOption modifies the claims generated by CreateUserClaims before they are encoded.
+type UserClaimsOption func(claims *jwt.UserClaims)
+
+// WithScopedSigner should be used when the User is issued by a scoped signing key. The permissions and limits are
+// cleared since the server applies those from the scope template, and will reject a scoped User which defines them.
+func WithScopedSigner() UserClaimsOption {
+	return func(claims *jwt.UserClaims) {
+		claims.UserPermissionLimits = jwt.UserPermissionLimits{}
+	}
+}
+
+// WithIssuerAccount should be used when the User is issued by an Account signing key, it sets the public key of the
+// Account so the server can map the signing key back to the Account.
+func WithIssuerAccount(accountPublicKey string) UserClaimsOption {
+	return func(claims *jwt.UserClaims) {
+		claims.IssuerAccount = accountPublicKey
+	}
+}
+
+// WithExpiry sets the time at which the User JWT expires.
+func WithExpiry(expiresAt time.Time) UserClaimsOption {
+	return func(claims *jwt.UserClaims) {
+		claims.Expires = expiresAt.Unix()
+	}
+}
+
+func CreateUserClaims(resource *v1alpha1.User, signingKey nkeys.KeyPair, opts ...UserClaimsOption) (claims *jwt.UserClaims, ujwt string, err error) {
 	claims = jwt.NewUserClaims(resource.Status.KeyPair.PublicKey)
 	claims.Name = resource.Name
 
 	spec := resource.Spec
-	specLimits := spec.Limits
-
-	claims.Limits = jwt.Limits{
-		UserLimits: jwt.UserLimits{
-			Src:    specLimits.Src,
-			Times:  ConvertToNatsTimeRanges(specLimits.Times),
-			Locale: specLimits.Locale,
-		},
-		NatsLimits: ConvertToNatsLimits(specLimits.NatsLimits, claims.Limits.NatsLimits),
-	}
 
-	if spec.BearerToken != nil {
-		claims.BearerToken = *spec.BearerToken
+	claims.UserPermissionLimits = ConvertToNATSUserPermissionLimits(v1alpha1.UserPermissionLimits{
+		Permissions: spec.Permissions,
+		Limits:      spec.Limits,
+		BearerToken: spec.BearerToken,
+	}, claims.UserPermissionLimits)
+
+	for _, opt := range opts {
+		opt(claims)
 	}
 
-	if spec.Permissions != nil {
-		claims.UserPermissionLimits = jwt.UserPermissionLimits{
-			Permissions: jwt.Permissions{
-				Pub: jwt.Permission{
-					Allow: spec.Permissions.Pub.Allow,
-					Deny:  spec.Permissions.Pub.Deny,
-				},
-				Sub: jwt.Permission{
-					Allow: spec.Permissions.Sub.Allow,
-					Deny:  spec.Permissions.Sub.Deny,
-				},
-			},
-			Limits:                 jwt.Limits{},
-			BearerToken:            false,
-			AllowedConnectionTypes: nil,
-		}
-
-		if spec.Permissions.Resp != nil {
-			claims.Resp = &jwt.ResponsePermission{
-				MaxMsgs: spec.Permissions.Resp.MaxMsgs,
-				Expires: spec.Permissions.Resp.TTL.Duration,
-			}
-		}
+	if err = ValidateClaims(claims); err != nil {
+		return nil, "", err
 	}
 
 	ujwt, err = claims.Encode(signingKey)
@@ -0,0 +1,54 @@
+package nsc
+
+import (
+	"testing"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+func TestCreateUserClaimsScopedSigner(t *testing.T) {
+	scopedKP, scopedKey := newTestKeyPair(t, nkeys.CreateAccount)
+	_, userPublicKey := newTestKeyPair(t, nkeys.CreateUser)
+
+	scope := jwt.NewUserScope()
+	scope.Key = scopedKey
+	scope.Role = "reader"
+
+	usr := &v1alpha1.User{
+		Spec: v1alpha1.UserSpec{
+			Permissions: &v1alpha1.UserPermissions{Pub: v1alpha1.Permission{Allow: []string{"orders.>"}}},
+		},
+	}
+	usr.Name = "usr"
+	usr.Status.KeyPair = &v1alpha1.KeyPair{PublicKey: userPublicKey}
+
+	unscoped, _, err := CreateUserClaims(usr, scopedKP)
+	if err != nil {
+		t.Fatalf("CreateUserClaims() error = %v", err)
+	}
+
+	if err := scope.ValidateScopedSigner(unscoped); err == nil {
+		t.Errorf("ValidateScopedSigner() accepted a user with its own permissions")
+	}
+
+	_, ujwt, err := CreateUserClaims(usr, scopedKP, WithScopedSigner())
+	if err != nil {
+		t.Fatalf("CreateUserClaims() error = %v", err)
+	}
+
+	claims, err := jwt.DecodeUserClaims(ujwt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !claims.HasEmptyPermissions() {
+		t.Errorf("scoped user permissions = %+v, want none", claims.UserPermissionLimits)
+	}
+
+	if err := scope.ValidateScopedSigner(claims); err != nil {
+		t.Errorf("ValidateScopedSigner() error = %v", err)
+	}
+}
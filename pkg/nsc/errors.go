@@ -0,0 +1,11 @@
+package nsc
+
+import "errors"
+
+// ErrInvalidSpec is wrapped by errors returned when a resource's spec cannot be converted into valid JWT claims, these
+// errors will not be resolved by retrying.
+var ErrInvalidSpec = errors.New("invalid spec")
+
+// ErrRequestTimeout is wrapped by errors returned when a NATS server does not reply to a request in time, which
+// usually means the server is unreachable.
+var ErrRequestTimeout = errors.New("request timed out")
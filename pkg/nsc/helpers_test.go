@@ -0,0 +1,24 @@
+package nsc
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/versori-oss/nats-account-operator/pkg/nsc/nsctest"
+)
+
+// connectFunc returns a ConnectFunc which connects to srv, counting the number of times it is called in dials.
+func connectFunc(srv *nsctest.Server, dials *atomic.Int32) ConnectFunc {
+	return func(opts ...nats.Option) (*Client, error) {
+		dials.Add(1)
+
+		conn, err := nats.Connect(srv.URL(), append(opts, nats.ReconnectWait(10*time.Millisecond))...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Client{conn: conn, timeout: DefaultRequestTimeout}, nil
+	}
+}
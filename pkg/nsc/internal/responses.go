@@ -37,3 +37,11 @@ type UpdateResponse struct {
 	Error  *ErrorInfo         `json:"error,omitempty"`
 	Data   UpdateResponseData `json:"data,omitempty"`
 }
+
+// ListResponse is the response payload from the $SYS.REQ.CLAIMS.LIST request, Data holds the public keys of all
+// accounts known to the resolver. Error and Data are mutually exclusive.
+type ListResponse struct {
+	Server ServerInfo `json:"server"`
+	Error  *ErrorInfo `json:"error,omitempty"`
+	Data   []string   `json:"data,omitempty"`
+}
@@ -0,0 +1,180 @@
+// Package nsctest provides a fake NATS server for testing code which talks to NATS servers with the nsc package.
+package nsctest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// HandlerFunc replies to a request with the returned data, or does not reply at all if it returns nil.
+type HandlerFunc func(data []byte) []byte
+
+// Server speaks just enough of the NATS client protocol to serve request/reply, so that clients can be tested without
+// running a NATS server. Authentication is not checked.
+type Server struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	handlers map[string]HandlerFunc
+	conns    map[net.Conn]struct{}
+}
+
+// NewServer starts a Server listening on a random local port, it is closed when the test completes.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	s := &Server{
+		ln:       ln,
+		handlers: make(map[string]HandlerFunc),
+		conns:    make(map[net.Conn]struct{}),
+	}
+
+	go s.serve()
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// URL returns the URL clients should connect to.
+func (s *Server) URL() string {
+	return "nats://" + s.ln.Addr().String()
+}
+
+// Handle registers h to reply to requests published to subj.
+func (s *Server) Handle(subj string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[subj] = h
+}
+
+// DropConnections closes all client connections without stopping the server, so that clients reconnect.
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+// Close stops the server and closes all client connections.
+func (s *Server) Close() {
+	_ = s.ln.Close()
+
+	s.DropConnections()
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	_, _ = io.WriteString(conn, `INFO {"server_id":"fake","version":"2.9.17","proto":1,"max_payload":1048576}`+"\r\n")
+
+	r := bufio.NewReader(conn)
+
+	// subscription subjects to their sid, the client subscribes to a wildcard inbox for replies
+	subs := make(map[string]string)
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			_, _ = io.WriteString(conn, "PONG\r\n")
+		case "SUB":
+			subs[fields[1]] = fields[len(fields)-1]
+		case "PUB":
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return
+			}
+
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+
+			var reply string
+			if len(fields) == 4 {
+				reply = fields[2]
+			}
+
+			s.mu.Lock()
+			h := s.handlers[fields[1]]
+			s.mu.Unlock()
+
+			if h == nil || reply == "" {
+				continue
+			}
+
+			data := h(payload[:size])
+			if data == nil {
+				continue
+			}
+
+			sid, ok := matchSubscription(subs, reply)
+			if !ok {
+				continue
+			}
+
+			_, _ = fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", reply, sid, len(data), data)
+		}
+	}
+}
+
+// matchSubscription returns the sid of the subscription matching subj, supporting a trailing single token wildcard.
+func matchSubscription(subs map[string]string, subj string) (string, bool) {
+	if sid, ok := subs[subj]; ok {
+		return sid, true
+	}
+
+	for pattern, sid := range subs {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if ok && strings.HasPrefix(subj, prefix) && !strings.Contains(subj[len(prefix):], ".") {
+			return sid, true
+		}
+	}
+
+	return "", false
+}
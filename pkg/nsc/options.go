@@ -29,3 +29,43 @@ func CABundle(cert []byte) nats.Option {
 		return nil
 	}
 }
+
+// ClientCertificate is similar to nats.ClientCert but accepts PEM encoded byte slices instead of file paths, it is
+// used for mutual TLS authentication with the NATS server.
+func ClientCertificate(cert, key []byte) nats.Option {
+	return func(options *nats.Options) error {
+		tlsCertCB := func() (tls.Certificate, error) {
+			certificate, err := tls.X509KeyPair(cert, key)
+			if err != nil {
+				return tls.Certificate{}, fmt.Errorf("failed to parse client certificate: %w", err)
+			}
+
+			return certificate, nil
+		}
+
+		if options.TLSConfig == nil {
+			options.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+
+		options.TLSCertCB = tlsCertCB
+		options.Secure = true
+
+		return nil
+	}
+}
+
+// TLSVerification sets the server name used to verify the NATS server certificate, and optionally disables the
+// verification altogether. An empty serverName uses the hostname of the server being connected to.
+func TLSVerification(serverName string, insecureSkipVerify bool) nats.Option {
+	return func(options *nats.Options) error {
+		if options.TLSConfig == nil {
+			options.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+
+		options.TLSConfig.ServerName = serverName
+		options.TLSConfig.InsecureSkipVerify = insecureSkipVerify //nolint:gosec // explicitly requested by the Operator spec
+		options.Secure = true
+
+		return nil
+	}
+}
@@ -0,0 +1,271 @@
+package nsc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var connectionStates = []nats.Status{
+	nats.DISCONNECTED,
+	nats.CONNECTED,
+	nats.CLOSED,
+	nats.RECONNECTING,
+	nats.CONNECTING,
+}
+
+// connectionState reports the state of each pooled connection, with a value of 1 for the current state and 0 for all
+// others.
+var connectionState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "nats_account_operator_connection_state",
+	Help: "State of the pooled NATS connection for each Operator push target, 1 for the current state and 0 otherwise.",
+}, []string{"namespace", "operator", "target", "state"})
+
+func init() {
+	metrics.Registry.MustRegister(connectionState)
+}
+
+// ConnectFunc opens a new Client for an Operator, the pool provides opts which must be passed through to Connect so
+// that the pool can track the connection state.
+type ConnectFunc func(opts ...nats.Option) (*Client, error)
+
+// ClientPool keeps one long-lived, authenticated Client per Operator push target so that Accounts can be pushed
+// without opening a new connection for every reconcile. Clients are keyed by the Operator UID and target name, and are
+// replaced whenever the Operator spec, its system account or the TLS configuration and credentials of the target
+// change. Clients returned by the pool are shared and must not be closed by the caller.
+type ClientPool struct {
+	mu      sync.Mutex
+	clients map[poolKey]*pooledClient
+	dials   map[poolKey]*dial
+}
+
+type poolKey struct {
+	uid    types.UID
+	target string
+}
+
+type pooledClient struct {
+	*Client
+
+	name        types.NamespacedName
+	fingerprint string
+}
+
+// dial is a connection in progress for a push target, callers needing the same connection wait for it to complete
+// rather than connecting again.
+type dial struct {
+	done        chan struct{}
+	name        types.NamespacedName
+	fingerprint string
+
+	// invalidated is set if the push target is invalidated while connecting, the resulting Client is then closed
+	// rather than added to the pool.
+	invalidated bool
+
+	client *Client
+	err    error
+}
+
+func NewClientPool() *ClientPool {
+	return &ClientPool{
+		clients: make(map[poolKey]*pooledClient),
+		dials:   make(map[poolKey]*dial),
+	}
+}
+
+// Get returns the pooled Client for the Operator push target, calling connect if there is no Client, the existing
+// Client has been closed or it was created for a different configuration. configHash should be the result of
+// HashConfig for the TLS material and credentials used to connect.
+//
+// connect is called without holding the pool lock, so that an unreachable push target only blocks callers which need a
+// connection to that same target. Concurrent callers for the same target and configuration share a single call.
+func (p *ClientPool) Get(operator *v1alpha1.Operator, target, configHash string, connect ConnectFunc) (*Client, error) {
+	key := poolKey{uid: operator.UID, target: target}
+	fingerprint := connectionFingerprint(operator, configHash)
+
+	for {
+		p.mu.Lock()
+
+		if pc, ok := p.clients[key]; ok {
+			if pc.fingerprint == fingerprint && !pc.conn.IsClosed() {
+				p.mu.Unlock()
+
+				return pc.Client, nil
+			}
+
+			p.remove(key, pc)
+		}
+
+		d, ok := p.dials[key]
+		if !ok {
+			break
+		}
+
+		p.mu.Unlock()
+
+		<-d.done
+
+		if d.fingerprint == fingerprint {
+			return d.client, d.err
+		}
+
+		// the completed connection was for a different configuration, so check the pool again
+	}
+
+	name := types.NamespacedName{Namespace: operator.Namespace, Name: operator.Name}
+
+	d := &dial{
+		done:        make(chan struct{}),
+		name:        name,
+		fingerprint: fingerprint,
+	}
+
+	p.dials[key] = d
+
+	p.mu.Unlock()
+
+	client, err := connect(
+		nats.MaxReconnects(-1),
+		nats.ConnectHandler(func(conn *nats.Conn) { setConnectionState(name, target, conn.Status()) }),
+		nats.ReconnectHandler(func(conn *nats.Conn) { setConnectionState(name, target, conn.Status()) }),
+		nats.DisconnectErrHandler(func(conn *nats.Conn, _ error) { setConnectionState(name, target, conn.Status()) }),
+		nats.ClosedHandler(func(conn *nats.Conn) { setConnectionState(name, target, conn.Status()) }),
+	)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.dials, key)
+
+	switch {
+	case err != nil:
+	case d.invalidated:
+		client.Close()
+
+		client, err = nil, fmt.Errorf("connection to push target %s was invalidated while connecting", target)
+	default:
+		setConnectionState(name, target, client.conn.Status())
+
+		p.clients[key] = &pooledClient{
+			Client:      client,
+			name:        name,
+			fingerprint: fingerprint,
+		}
+	}
+
+	d.client, d.err = client, err
+
+	close(d.done)
+
+	return client, err
+}
+
+// Invalidate closes the pooled Client for the Operator push target, the next call to Get will open a new connection.
+// This should be called when a request fails in case the connection is no longer usable, such as the system user being
+// re-keyed.
+func (p *ClientPool) Invalidate(uid types.UID, target string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := poolKey{uid: uid, target: target}
+
+	if pc, ok := p.clients[key]; ok {
+		p.remove(key, pc)
+	}
+
+	if d, ok := p.dials[key]; ok {
+		d.invalidated = true
+	}
+}
+
+// InvalidateName closes all pooled Clients for the Operator with the given name, this is used once an Operator has been
+// deleted and its UID is no longer known.
+func (p *ClientPool) InvalidateName(name types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, pc := range p.clients {
+		if pc.name == name {
+			p.remove(key, pc)
+		}
+	}
+
+	for _, d := range p.dials {
+		if d.name == name {
+			d.invalidated = true
+		}
+	}
+}
+
+// Close closes all pooled Clients.
+func (p *ClientPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, pc := range p.clients {
+		p.remove(key, pc)
+	}
+
+	for _, d := range p.dials {
+		d.invalidated = true
+	}
+}
+
+// remove must be called with p.mu held.
+func (p *ClientPool) remove(key poolKey, pc *pooledClient) {
+	delete(p.clients, key)
+
+	pc.Close()
+
+	for _, state := range connectionStates {
+		connectionState.DeleteLabelValues(pc.name.Namespace, pc.name.Name, key.target, state.String())
+	}
+}
+
+func setConnectionState(name types.NamespacedName, target string, current nats.Status) {
+	for _, state := range connectionStates {
+		value := 0.0
+		if state == current {
+			value = 1
+		}
+
+		connectionState.WithLabelValues(name.Namespace, name.Name, target, state.String()).Set(value)
+	}
+}
+
+// connectionFingerprint identifies the configuration a Client was created for. The Operator generation changes with
+// any change to its spec, and the status references change if the system account or system user are replaced.
+func connectionFingerprint(operator *v1alpha1.Operator, configHash string) string {
+	var systemAccount, systemUser string
+
+	if ref := operator.Status.ResolvedSystemAccount; ref != nil {
+		systemAccount = ref.Namespace + "/" + ref.Name
+	}
+
+	if ref := operator.Status.SystemUserRef; ref != nil {
+		systemUser = ref.Namespace + "/" + ref.Name
+	}
+
+	return fmt.Sprintf("%d|%s|%s|%s", operator.Generation, systemAccount, systemUser, configHash)
+}
+
+// HashConfig returns a hash of the TLS material and credentials used to connect, so that pooled Clients are replaced
+// when any of it changes.
+func HashConfig(material ...[]byte) string {
+	h := sha256.New()
+
+	for _, m := range material {
+		// prefix each value with its length so that the boundaries between values are part of the hash
+		_, _ = fmt.Fprintf(h, "%d:", len(m))
+		_, _ = h.Write(m)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,325 @@
+package nsc
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc/nsctest"
+)
+
+func newTestOperator(name string, uid types.UID) *v1alpha1.Operator {
+	return &v1alpha1.Operator{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  "nats",
+			UID:        uid,
+			Generation: 1,
+		},
+	}
+}
+
+func newTestPool(t *testing.T) *ClientPool {
+	pool := NewClientPool()
+
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func mustGet(t *testing.T, pool *ClientPool, operator *v1alpha1.Operator, target, configHash string, connect ConnectFunc) *Client {
+	t.Helper()
+
+	client, err := pool.Get(operator, target, configHash, connect)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	return client
+}
+
+// eventually fails the test if condition does not return true within a second.
+func eventually(t *testing.T, condition func() bool, msg string) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestClientPoolGet(t *testing.T) {
+	srv := nsctest.NewServer(t)
+	pool := newTestPool(t)
+
+	var dials atomic.Int32
+
+	connect := connectFunc(srv, &dials)
+	operator := newTestOperator("operator", "operator-uid")
+
+	first := mustGet(t, pool, operator, "default", "hash", connect)
+	second := mustGet(t, pool, operator, "default", "hash", connect)
+
+	if first != second || dials.Load() != 1 {
+		t.Fatalf("Get() connected %d times, want a single pooled connection", dials.Load())
+	}
+
+	other := mustGet(t, pool, operator, "other", "hash", connect)
+	if other == first {
+		t.Errorf("Get() returned the same client for different push targets")
+	}
+
+	if dials.Load() != 2 {
+		t.Errorf("Get() connected %d times, want 2", dials.Load())
+	}
+}
+
+func TestClientPoolFingerprint(t *testing.T) {
+	tests := []struct {
+		name       string
+		configHash string
+		mutate     func(operator *v1alpha1.Operator)
+	}{
+		{
+			name:       "spec changed",
+			configHash: "hash",
+			mutate:     func(operator *v1alpha1.Operator) { operator.Generation++ },
+		},
+		{
+			name:       "system account changed",
+			configHash: "hash",
+			mutate: func(operator *v1alpha1.Operator) {
+				operator.Status.ResolvedSystemAccount = &v1alpha1.InferredObjectReference{Namespace: "nats", Name: "sys"}
+			},
+		},
+		{
+			name:       "system user changed",
+			configHash: "hash",
+			mutate: func(operator *v1alpha1.Operator) {
+				operator.Status.SystemUserRef = &v1alpha1.InferredObjectReference{Namespace: "nats", Name: "sys-user"}
+			},
+		},
+		{
+			name:       "config changed",
+			configHash: "other-hash",
+			mutate:     func(*v1alpha1.Operator) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := nsctest.NewServer(t)
+			pool := newTestPool(t)
+
+			var dials atomic.Int32
+
+			connect := connectFunc(srv, &dials)
+			operator := newTestOperator("operator", "operator-uid")
+
+			previous := mustGet(t, pool, operator, "default", "hash", connect)
+
+			tt.mutate(operator)
+
+			next := mustGet(t, pool, operator, "default", tt.configHash, connect)
+
+			if next == previous || dials.Load() != 2 {
+				t.Fatalf("Get() did not reconnect after the configuration changed")
+			}
+
+			if !previous.conn.IsClosed() {
+				t.Errorf("previous client was not closed")
+			}
+		})
+	}
+}
+
+func TestClientPoolInvalidate(t *testing.T) {
+	srv := nsctest.NewServer(t)
+	pool := newTestPool(t)
+
+	var dials atomic.Int32
+
+	connect := connectFunc(srv, &dials)
+	operator := newTestOperator("operator", "operator-uid")
+	other := newTestOperator("other", "other-uid")
+
+	client := mustGet(t, pool, operator, "default", "hash", connect)
+	otherClient := mustGet(t, pool, other, "default", "hash", connect)
+
+	pool.Invalidate(operator.UID, "default")
+
+	if !client.conn.IsClosed() {
+		t.Errorf("Invalidate() did not close the client")
+	}
+
+	if otherClient.conn.IsClosed() {
+		t.Errorf("Invalidate() closed the client of another operator")
+	}
+
+	if next := mustGet(t, pool, operator, "default", "hash", connect); next == client {
+		t.Errorf("Get() returned the invalidated client")
+	}
+
+	pool.InvalidateName(types.NamespacedName{Namespace: other.Namespace, Name: other.Name})
+
+	if !otherClient.conn.IsClosed() {
+		t.Errorf("InvalidateName() did not close the client")
+	}
+
+	// a client closed outside of the pool is replaced too
+	client = mustGet(t, pool, operator, "default", "hash", connect)
+	client.Close()
+
+	if next := mustGet(t, pool, operator, "default", "hash", connect); next == client {
+		t.Errorf("Get() returned a closed client")
+	}
+}
+
+func TestClientPoolReconnect(t *testing.T) {
+	srv := nsctest.NewServer(t)
+	pool := newTestPool(t)
+
+	var dials atomic.Int32
+
+	var reconnects atomic.Int32
+
+	connect := func(opts ...nats.Option) (*Client, error) {
+		return connectFunc(srv, &dials)(append(opts, nats.ReconnectHandler(func(*nats.Conn) { reconnects.Add(1) }))...)
+	}
+
+	operator := newTestOperator("operator", "operator-uid")
+
+	client := mustGet(t, pool, operator, "default", "hash", connect)
+
+	srv.DropConnections()
+
+	eventually(t, func() bool { return reconnects.Load() > 0 && client.IsConnected() }, "client did not reconnect")
+
+	if next := mustGet(t, pool, operator, "default", "hash", connect); next != client || dials.Load() != 1 {
+		t.Errorf("Get() replaced a client which reconnected")
+	}
+}
+
+func TestClientPoolConcurrentGet(t *testing.T) {
+	srv := nsctest.NewServer(t)
+	pool := newTestPool(t)
+
+	operator := newTestOperator("operator", "operator-uid")
+
+	var slowDials, fastDials atomic.Int32
+
+	release := make(chan struct{})
+	connecting := make(chan struct{}, 1)
+
+	slow := func(opts ...nats.Option) (*Client, error) {
+		connecting <- struct{}{}
+
+		<-release
+
+		return connectFunc(srv, &slowDials)(opts...)
+	}
+
+	var wg sync.WaitGroup
+
+	clients := make([]*Client, 2)
+	errs := make([]error, 2)
+
+	for i := range clients {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			clients[i], errs[i] = pool.Get(operator, "slow", "hash", slow)
+		}(i)
+	}
+
+	<-connecting
+
+	// a connection to another target must not wait for the slow target
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		mustGet(t, pool, operator, "fast", "hash", connectFunc(srv, &fastDials))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Get() for another push target blocked while connecting to the slow target")
+	}
+
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Get() %d error = %v", i, err)
+		}
+	}
+
+	if clients[0] != clients[1] || slowDials.Load() != 1 {
+		t.Errorf("concurrent Get() connected %d times, want a single shared connection", slowDials.Load())
+	}
+}
+
+func TestClientPoolInvalidateWhileConnecting(t *testing.T) {
+	srv := nsctest.NewServer(t)
+	pool := newTestPool(t)
+
+	operator := newTestOperator("operator", "operator-uid")
+
+	var dials atomic.Int32
+
+	var client *Client
+
+	connect := func(opts ...nats.Option) (*Client, error) {
+		var err error
+
+		client, err = connectFunc(srv, &dials)(opts...)
+
+		// the Operator is deleted while connecting
+		pool.InvalidateName(types.NamespacedName{Namespace: operator.Namespace, Name: operator.Name})
+
+		return client, err
+	}
+
+	if _, err := pool.Get(operator, "default", "hash", connect); err == nil {
+		t.Fatal("Get() error = nil, want an error for an invalidated connection")
+	}
+
+	if !client.conn.IsClosed() {
+		t.Errorf("connection invalidated while connecting was not closed")
+	}
+
+	if next := mustGet(t, pool, operator, "default", "hash", connectFunc(srv, &dials)); next == client {
+		t.Errorf("Get() returned the invalidated client")
+	}
+}
+
+func TestHashConfig(t *testing.T) {
+	if HashConfig([]byte("ab"), []byte("c")) == HashConfig([]byte("a"), []byte("bc")) {
+		t.Errorf("HashConfig() does not include the boundaries between values")
+	}
+
+	if HashConfig([]byte("a"), nil) == HashConfig([]byte("a")) {
+		t.Errorf("HashConfig() does not include empty values")
+	}
+
+	if HashConfig([]byte("a"), []byte("b")) != HashConfig([]byte("a"), []byte("b")) {
+		t.Errorf("HashConfig() is not deterministic")
+	}
+}
@@ -0,0 +1,84 @@
+package nsc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+const (
+	// ServerConfigKey is the key of the rendered configuration within the ConfigMap.
+	ServerConfigKey = "nats.conf"
+
+	// DefaultResolverDir is the directory used by the full and cache resolvers if v1alpha1.ServerConfig.Dir is not
+	// set.
+	DefaultResolverDir = "/data/resolver"
+)
+
+// RenderServerConfig renders the operator, system_account and resolver settings of a nats-server configuration.
+// accounts maps the public key of each Account to its JWT, these are only included by the memory resolver, which must
+// also be given the system account.
+func RenderServerConfig(config *v1alpha1.ServerConfig, operatorJWT, systemAccount string, accounts map[string]string) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by nats-account-operator, do not edit.\n")
+	fmt.Fprintf(&b, "operator: %s\n", operatorJWT)
+	fmt.Fprintf(&b, "system_account: %s\n", systemAccount)
+
+	resolverType := config.ResolverType
+	if resolverType == "" {
+		resolverType = v1alpha1.ResolverTypeFull
+	}
+
+	if resolverType == v1alpha1.ResolverTypeMemory {
+		publicKeys := make([]string, 0, len(accounts))
+		for publicKey := range accounts {
+			publicKeys = append(publicKeys, publicKey)
+		}
+
+		// sort the accounts so that the configuration only changes when the JWTs do
+		sort.Strings(publicKeys)
+
+		b.WriteString("resolver: MEMORY\n")
+		b.WriteString("resolver_preload: {\n")
+
+		for _, publicKey := range publicKeys {
+			fmt.Fprintf(&b, "    %s: %s\n", publicKey, accounts[publicKey])
+		}
+
+		b.WriteString("}\n")
+
+		return b.String()
+	}
+
+	dir := config.Dir
+	if dir == "" {
+		dir = DefaultResolverDir
+	}
+
+	b.WriteString("resolver: {\n")
+	fmt.Fprintf(&b, "    type: %s\n", resolverType)
+	fmt.Fprintf(&b, "    dir: %q\n", dir)
+
+	if resolverType == v1alpha1.ResolverTypeFull {
+		fmt.Fprintf(&b, "    allow_delete: %t\n", config.AllowDelete)
+
+		if config.Interval != "" {
+			fmt.Fprintf(&b, "    interval: %q\n", config.Interval)
+		}
+	}
+
+	if config.Limit != nil {
+		fmt.Fprintf(&b, "    limit: %d\n", *config.Limit)
+	}
+
+	if resolverType == v1alpha1.ResolverTypeCache && config.TTL != "" {
+		fmt.Fprintf(&b, "    ttl: %q\n", config.TTL)
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
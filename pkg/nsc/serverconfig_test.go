@@ -0,0 +1,85 @@
+package nsc
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/pointer"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestRenderServerConfig(t *testing.T) {
+	accounts := map[string]string{
+		"ACCOUNT_C": "account-c.jwt",
+		"ACCOUNT_A": "account-a.jwt",
+		"ACCOUNT_B": "account-b.jwt",
+	}
+
+	tests := []struct {
+		name   string
+		config v1alpha1.ServerConfig
+	}{
+		{
+			name:   "default",
+			config: v1alpha1.ServerConfig{},
+		},
+		{
+			name: "full",
+			config: v1alpha1.ServerConfig{
+				ResolverType: v1alpha1.ResolverTypeFull,
+				Dir:          "/var/lib/nats/jwt",
+				AllowDelete:  true,
+				Interval:     "2m",
+				Limit:        pointer.Int32(1000),
+				TTL:          "1h",
+			},
+		},
+		{
+			name: "cache",
+			config: v1alpha1.ServerConfig{
+				ResolverType: v1alpha1.ResolverTypeCache,
+				AllowDelete:  true,
+				Interval:     "2m",
+				Limit:        pointer.Int32(100),
+				TTL:          "1h",
+			},
+		},
+		{
+			name: "memory",
+			config: v1alpha1.ServerConfig{
+				ResolverType: v1alpha1.ResolverTypeMemory,
+				Dir:          "/var/lib/nats/jwt",
+				Limit:        pointer.Int32(100),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderServerConfig(&tt.config, "operator.jwt", "SYSTEM_ACCOUNT", accounts)
+
+			golden := filepath.Join("testdata", "serverconfig", tt.name+".conf")
+
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(string(want), got); diff != "" {
+				t.Errorf("RenderServerConfig() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
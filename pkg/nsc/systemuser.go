@@ -0,0 +1,52 @@
+package nsc
+
+import (
+	"context"
+	"fmt"
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	clientsetv1alpha1 "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned/typed/accounts/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientsetv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// SystemUserLoader loads the credentials of the User which the Operator manages within its system account, these are
+// used to connect to the NATS servers when pushing or deleting account JWTs.
+type SystemUserLoader struct {
+	accounts clientsetv1alpha1.AccountsV1alpha1Interface
+	core     clientsetv1.CoreV1Interface
+}
+
+func NewSystemUserLoader(
+	accounts clientsetv1alpha1.AccountsV1alpha1Interface,
+	core clientsetv1.CoreV1Interface,
+) *SystemUserLoader {
+	return &SystemUserLoader{
+		accounts: accounts,
+		core:     core,
+	}
+}
+
+func (s *SystemUserLoader) Load(ctx context.Context, operator *v1alpha1.Operator) (creds []byte, err error) {
+	if operator.Status.SystemUserRef == nil {
+		return nil, fmt.Errorf("operator %s/%s does not have a system user", operator.Namespace, operator.Name)
+	}
+
+	ref := operator.Status.SystemUserRef
+
+	usr, err := s.accounts.Users(ref.Namespace).Get(ctx, ref.Name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	credsSecret, err := s.core.Secrets(usr.Namespace).Get(ctx, usr.Spec.CredentialsSecretName, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	credsBytes, ok := credsSecret.Data[v1alpha1.NatsSecretCredsKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s is invalid, missing field: %s", credsSecret.Namespace, credsSecret.Name, v1alpha1.NatsSecretCredsKey)
+	}
+
+	return credsBytes, nil
+}
@@ -0,0 +1,149 @@
+package nsc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// ValidateClaims runs the jwt library validation on claims, returning an error wrapping ErrInvalidSpec which lists
+// every blocking issue. Warnings and time checks, such as an expired JWT, are ignored.
+func ValidateClaims(claims jwt.Claims) error {
+	vr := jwt.CreateValidationResults()
+	claims.Validate(vr)
+
+	return validationError(vr)
+}
+
+// ValidateAccount checks that the Account spec converts into valid Account claims, the same conversion and validation
+// as CreateAccountClaims. Accounts which have not been assigned a keypair yet are validated using a temporary one, in
+// which case activation tokens are only checked to be decodable since they must be issued to the Account's public key.
+func ValidateAccount(resource *v1alpha1.Account) error {
+	resource = resource.DeepCopy()
+
+	keyPairKnown := resource.Status.KeyPair != nil
+	if !keyPairKnown {
+		publicKey, err := temporaryPublicKey(nkeys.CreateAccount)
+		if err != nil {
+			return err
+		}
+
+		resource.Status.KeyPair = &v1alpha1.KeyPair{PublicKey: publicKey}
+	}
+
+	claims, err := newAccountClaims(resource)
+	if err != nil {
+		return err
+	}
+
+	vr := jwt.CreateValidationResults()
+
+	if !keyPairKnown {
+		for _, imp := range claims.Imports {
+			if imp.Token == "" {
+				continue
+			}
+
+			if _, err := jwt.DecodeActivationClaims(imp.Token); err != nil {
+				vr.AddError("import %q contains an invalid activation token", imp.Subject)
+			}
+
+			imp.Token = ""
+		}
+	}
+
+	claims.Validate(vr)
+
+	return validationError(vr)
+}
+
+// ValidateUser checks that the User spec converts into valid User claims, the same conversion and validation as
+// CreateUserClaims. Users which have not been assigned a keypair yet are validated using a temporary one.
+func ValidateUser(resource *v1alpha1.User) error {
+	resource = resource.DeepCopy()
+
+	if resource.Status.KeyPair == nil {
+		publicKey, err := temporaryPublicKey(nkeys.CreateUser)
+		if err != nil {
+			return err
+		}
+
+		resource.Status.KeyPair = &v1alpha1.KeyPair{PublicKey: publicKey}
+	}
+
+	signingKey, err := nkeys.CreateAccount()
+	if err != nil {
+		return fmt.Errorf("failed to create temporary signing key: %w", err)
+	}
+
+	_, _, err = CreateUserClaims(resource, signingKey)
+
+	return err
+}
+
+// ValidateOperator checks that the Operator spec converts into valid Operator claims, the same conversion and
+// validation as CreateOperatorClaims. A temporary keypair is used for the system account, and for the Operator if it
+// has not been assigned one yet.
+func ValidateOperator(resource *v1alpha1.Operator) error {
+	resource = resource.DeepCopy()
+
+	if resource.Status.KeyPair == nil {
+		publicKey, err := temporaryPublicKey(nkeys.CreateOperator)
+		if err != nil {
+			return err
+		}
+
+		resource.Status.KeyPair = &v1alpha1.KeyPair{PublicKey: publicKey}
+	}
+
+	systemAccount, err := temporaryPublicKey(nkeys.CreateAccount)
+	if err != nil {
+		return err
+	}
+
+	claims, err := newOperatorClaims(resource, systemAccount)
+	if err != nil {
+		return err
+	}
+
+	return ValidateClaims(claims)
+}
+
+// ValidateSigningKeyScope checks that the permissions and limits in the scope template are valid, the same checks the
+// jwt library applies to the permissions and limits of a User.
+func ValidateSigningKeyScope(scope *v1alpha1.SigningKeyScope) error {
+	user := jwt.User{
+		UserPermissionLimits: ConvertToNATSUserPermissionLimits(scope.Template, jwt.NewUserScope().Template),
+	}
+
+	vr := jwt.CreateValidationResults()
+	user.Validate(vr)
+
+	return validationError(vr)
+}
+
+func validationError(vr *jwt.ValidationResults) error {
+	errs := vr.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidSpec, strings.Join(msgs, ", "))
+}
+
+func temporaryPublicKey(create func() (nkeys.KeyPair, error)) (string, error) {
+	kp, err := create()
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary keypair: %w", err)
+	}
+
+	return kp.PublicKey()
+}
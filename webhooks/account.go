@@ -0,0 +1,161 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+)
+
+//+kubebuilder:webhook:path=/validate-accounts-nats-io-v1alpha1-account,mutating=false,failurePolicy=fail,sideEffects=None,groups=accounts.nats.io,resources=accounts,verbs=create;update,versions=v1alpha1,name=vaccount.kb.io,admissionReviewVersions=v1
+
+// AccountValidator validates Accounts on create and update.
+type AccountValidator struct {
+	// Client is used to check that the Secret names are not used by another resource.
+	Client client.Reader
+}
+
+var _ admission.CustomValidator = &AccountValidator{}
+
+func (v *AccountValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	acc, ok := obj.(*v1alpha1.Account)
+	if !ok {
+		return fmt.Errorf("expected an Account but got %T", obj)
+	}
+
+	return v.validate(ctx, acc, nil)
+}
+
+func (v *AccountValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	acc, ok := newObj.(*v1alpha1.Account)
+	if !ok {
+		return fmt.Errorf("expected an Account but got %T", newObj)
+	}
+
+	old, ok := oldObj.(*v1alpha1.Account)
+	if !ok {
+		return fmt.Errorf("expected an Account but got %T", oldObj)
+	}
+
+	return v.validate(ctx, acc, old)
+}
+
+func (v *AccountValidator) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+// validate checks acc, old is nil when acc is being created.
+func (v *AccountValidator) validate(ctx context.Context, acc, old *v1alpha1.Account) error {
+	spec := field.NewPath("spec")
+
+	var errs field.ErrorList
+
+	if old != nil {
+		errs = append(errs, validateImmutable(acc.Spec.JWTSecretName, old.Spec.JWTSecretName, spec.Child("jwtSecretName"))...)
+		errs = append(errs, validateImmutable(acc.Spec.SeedSecretName, old.Spec.SeedSecretName, spec.Child("seedSecretName"))...)
+
+		// the xkey secret can be added to enable encryption or removed to disable it, but not renamed
+		if newName, oldName := xKeySecretName(acc), xKeySecretName(old); newName != "" && oldName != "" {
+			errs = append(errs, validateImmutable(newName, oldName, spec.Child("authorization", "xKeySecretName"))...)
+		}
+	}
+
+	if !shouldValidateSpec(acc, old == nil || !equality.Semantic.DeepEqual(acc.Spec, old.Spec)) {
+		return invalid("Account", acc.Name, errs)
+	}
+
+	errs = append(errs, validateIssuerRef(acc.Spec.Issuer, spec.Child("issuer", "ref"), "Operator", "SigningKey")...)
+
+	issuerErrs, err := v.validateOperatorIssuer(ctx, acc, spec.Child("issuer", "ref", "kind"))
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, issuerErrs...)
+	errs = append(errs, validateLabelSelector(acc.Spec.UsersNamespaceSelector, spec.Child("usersNamespaceSelector"))...)
+	errs = append(errs, validateLabelSelector(acc.Spec.UsersSelector, spec.Child("usersSelector"))...)
+	errs = append(errs, validateLabelSelector(acc.Spec.SigningKeysSelector, spec.Child("signingKeysSelector"))...)
+
+	for i, imp := range acc.Spec.Imports {
+		if imp.Token != "" && imp.TokenSecretRef != nil {
+			errs = append(errs, field.Forbidden(spec.Child("imports").Index(i).Child("tokenSecretRef"), "must not be set with token"))
+		}
+	}
+
+	specErrs, err := specError(nsc.ValidateAccount(acc), spec)
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, specErrs...)
+
+	secretErrs, err := validateSecretNames(ctx, v.Client, secretOwner{kind: "Account", name: acc.Name}, acc.Namespace, accountSecretNames(acc, spec), accountSecretNames(old, spec))
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, secretErrs...)
+
+	return invalid("Account", acc.Name, errs)
+}
+
+// validateOperatorIssuer rejects an Account issued directly by an Operator which requires strict signing key usage,
+// since the servers would reject the Account JWT. An Operator which cannot be found is left to the controller.
+func (v *AccountValidator) validateOperatorIssuer(ctx context.Context, acc *v1alpha1.Account, path *field.Path) (field.ErrorList, error) {
+	ref := acc.Spec.Issuer.Ref
+	if ref.Kind != "Operator" {
+		return nil, nil
+	}
+
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = acc.Namespace
+	}
+
+	operator := new(v1alpha1.Operator)
+
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, operator); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	if !operator.Spec.StrictSigningKeyUsage {
+		return nil, nil
+	}
+
+	return field.ErrorList{
+		field.Forbidden(path, fmt.Sprintf("operator %s/%s requires strict signing key usage, accounts must be issued by a SigningKey", namespace, ref.Name)),
+	}, nil
+}
+
+func xKeySecretName(acc *v1alpha1.Account) string {
+	if acc.Spec.Authorization == nil {
+		return ""
+	}
+
+	return acc.Spec.Authorization.XKeySecretName
+}
+
+// accountSecretNames returns the Secret names claimed by acc, or nil if acc is nil.
+func accountSecretNames(acc *v1alpha1.Account, spec *field.Path) []secretName {
+	if acc == nil {
+		return nil
+	}
+
+	names := []secretName{
+		{name: acc.Spec.JWTSecretName, path: spec.Child("jwtSecretName")},
+		{name: acc.Spec.SeedSecretName, path: spec.Child("seedSecretName")},
+	}
+
+	if name := xKeySecretName(acc); name != "" {
+		names = append(names, secretName{name: name, path: spec.Child("authorization", "xKeySecretName")})
+	}
+
+	return names
+}
@@ -0,0 +1,180 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// newTestAccount returns a valid Account issued by the Operator "op" with mutate applied.
+func newTestAccount(name string, mutate ...func(acc *v1alpha1.Account)) *v1alpha1.Account {
+	acc := &v1alpha1.Account{
+		ObjectMeta: testObjectMeta(name),
+		Spec: v1alpha1.AccountSpec{
+			Issuer:         testIssuer("Operator", "op"),
+			JWTSecretName:  name + "-jwt",
+			SeedSecretName: name + "-seed",
+		},
+	}
+
+	for _, m := range mutate {
+		m(acc)
+	}
+
+	return acc
+}
+
+func withAccountXKeySecret(name string) func(acc *v1alpha1.Account) {
+	return func(acc *v1alpha1.Account) {
+		acc.Spec.Authorization = &v1alpha1.AccountAuthorization{AuthUsers: []string{"auth"}, XKeySecretName: name}
+	}
+}
+
+// withInvalidAccountSpec sets a spec which is rejected by the jwt validation and an invalid label selector.
+func withInvalidAccountSpec(acc *v1alpha1.Account) {
+	acc.Spec.UsersSelector = invalidSelector
+	acc.Spec.Exports = []v1alpha1.AccountExport{{Name: "invalid", Type: v1alpha1.ImportExportTypeStream}}
+}
+
+func TestAccountValidator(t *testing.T) {
+	tests := []struct {
+		name       string
+		acc        *v1alpha1.Account
+		old        *v1alpha1.Account
+		existing   []client.Object
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			acc:  newTestAccount("acc", withAccountXKeySecret("acc-xkey")),
+		},
+		{
+			name: "invalid issuer",
+			acc: newTestAccount("acc", func(acc *v1alpha1.Account) {
+				acc.Spec.Issuer = v1alpha1.IssuerReference{Ref: v1alpha1.TypedObjectReference{APIVersion: "v1", Kind: "User"}}
+			}),
+			wantFields: []string{"spec.issuer.ref.apiVersion", "spec.issuer.ref.kind", "spec.issuer.ref.name"},
+		},
+		{
+			name:       "issued by an operator requiring strict signing key usage",
+			acc:        newTestAccount("acc"),
+			existing:   []client.Object{newTestStrictOperator()},
+			wantFields: []string{"spec.issuer.ref.kind"},
+		},
+		{
+			name:     "issued by a signing key of an operator requiring strict signing key usage",
+			acc:      newTestAccount("acc", func(acc *v1alpha1.Account) { acc.Spec.Issuer = testIssuer("SigningKey", "op-sk") }),
+			existing: []client.Object{newTestStrictOperator()},
+		},
+		{
+			name:       "invalid spec",
+			acc:        newTestAccount("acc", withInvalidAccountSpec),
+			wantFields: []string{"spec", "spec.usersSelector"},
+		},
+		{
+			name: "token with token secret",
+			acc: newTestAccount("acc", func(acc *v1alpha1.Account) {
+				acc.Spec.Imports = []v1alpha1.AccountImport{{
+					Name:           "import",
+					Subject:        "foo",
+					Account:        "ACCOUNT",
+					Token:          "token",
+					TokenSecretRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "token"}},
+					Type:           v1alpha1.ImportExportTypeStream,
+				}}
+			}),
+			wantFields: []string{"spec", "spec.imports[0].tokenSecretRef"},
+		},
+		{
+			name: "missing secret names",
+			acc: newTestAccount("acc", func(acc *v1alpha1.Account) {
+				acc.Spec.JWTSecretName = ""
+				acc.Spec.SeedSecretName = ""
+			}),
+			wantFields: []string{"spec.jwtSecretName", "spec.seedSecretName"},
+		},
+		{
+			name:       "secret used by another account",
+			acc:        newTestAccount("acc", withAccountXKeySecret("other-seed")),
+			existing:   []client.Object{newTestAccount("other")},
+			wantFields: []string{"spec.authorization.xKeySecretName"},
+		},
+		{
+			name: "shared secret unchanged",
+			acc: newTestAccount("acc", func(acc *v1alpha1.Account) {
+				acc.Spec.JWTSecretName = "other-jwt"
+				acc.Spec.Limits = &v1alpha1.OperatorLimits{}
+			}),
+			old: newTestAccount("acc", func(acc *v1alpha1.Account) {
+				acc.Spec.JWTSecretName = "other-jwt"
+			}),
+			existing: []client.Object{newTestAccount("other")},
+		},
+		{
+			name: "secret names are immutable",
+			acc: newTestAccount("acc", withAccountXKeySecret("renamed-xkey"), func(acc *v1alpha1.Account) {
+				acc.Spec.JWTSecretName = "renamed-jwt"
+				acc.Spec.SeedSecretName = "renamed-seed"
+			}),
+			old:        newTestAccount("acc", withAccountXKeySecret("acc-xkey")),
+			wantFields: []string{"spec.authorization.xKeySecretName", "spec.jwtSecretName", "spec.seedSecretName"},
+		},
+		{
+			name: "xkey secret added",
+			acc:  newTestAccount("acc", withAccountXKeySecret("acc-xkey")),
+			old:  newTestAccount("acc"),
+		},
+		{
+			name: "spec unchanged",
+			acc: newTestAccount("acc", withInvalidAccountSpec, func(acc *v1alpha1.Account) {
+				acc.Finalizers = nil
+			}),
+			old: newTestAccount("acc", withInvalidAccountSpec, func(acc *v1alpha1.Account) {
+				acc.Finalizers = []string{"accounts.nats.io/finalizer"}
+			}),
+		},
+		{
+			name: "spec changed",
+			acc: newTestAccount("acc", withInvalidAccountSpec, func(acc *v1alpha1.Account) {
+				acc.Spec.Limits = &v1alpha1.OperatorLimits{}
+			}),
+			old:        newTestAccount("acc", withInvalidAccountSpec),
+			wantFields: []string{"spec", "spec.usersSelector"},
+		},
+		{
+			name: "deleting",
+			acc: newTestAccount("acc", withInvalidAccountSpec, func(acc *v1alpha1.Account) {
+				acc.DeletionTimestamp = &deleting
+			}),
+			old: newTestAccount("acc"),
+		},
+		{
+			name: "deleting with secret renamed",
+			acc: newTestAccount("acc", func(acc *v1alpha1.Account) {
+				acc.DeletionTimestamp = &deleting
+				acc.Spec.JWTSecretName = "renamed-jwt"
+			}),
+			old:        newTestAccount("acc"),
+			wantFields: []string{"spec.jwtSecretName"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &AccountValidator{Client: newFakeClient(tt.existing...)}
+
+			var err error
+			if tt.old == nil {
+				err = v.ValidateCreate(context.Background(), tt.acc)
+			} else {
+				err = v.ValidateUpdate(context.Background(), tt.old, tt.acc)
+			}
+
+			assertFieldErrors(t, err, tt.wantFields)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+//+kubebuilder:webhook:path=/mutate-accounts-nats-io-v1alpha1-account,mutating=true,failurePolicy=fail,sideEffects=None,groups=accounts.nats.io,resources=accounts,verbs=create;update,versions=v1alpha1,name=maccount.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/mutate-accounts-nats-io-v1alpha1-user,mutating=true,failurePolicy=fail,sideEffects=None,groups=accounts.nats.io,resources=users,verbs=create;update,versions=v1alpha1,name=muser.kb.io,admissionReviewVersions=v1
+
+// AccountDefaulter derives the Secret names of an Account from its name, and fills in the namespace and API version of
+// its issuer reference.
+type AccountDefaulter struct{}
+
+var _ admission.CustomDefaulter = &AccountDefaulter{}
+
+func (d *AccountDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	acc, ok := obj.(*v1alpha1.Account)
+	if !ok {
+		return fmt.Errorf("expected an Account but got %T", obj)
+	}
+
+	defaultSecretName(&acc.Spec.JWTSecretName, acc.Name, v1alpha1.JWTSecretNameSuffix)
+	defaultSecretName(&acc.Spec.SeedSecretName, acc.Name, v1alpha1.SeedSecretNameSuffix)
+	defaultIssuerRef(&acc.Spec.Issuer, namespaceOf(ctx, acc.Namespace))
+
+	return nil
+}
+
+// UserDefaulter derives the Secret names of a User from its name, and fills in the namespace and API version of its
+// issuer reference.
+type UserDefaulter struct{}
+
+var _ admission.CustomDefaulter = &UserDefaulter{}
+
+func (d *UserDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	usr, ok := obj.(*v1alpha1.User)
+	if !ok {
+		return fmt.Errorf("expected a User but got %T", obj)
+	}
+
+	defaultSecretName(&usr.Spec.JWTSecretName, usr.Name, v1alpha1.JWTSecretNameSuffix)
+	defaultSecretName(&usr.Spec.SeedSecretName, usr.Name, v1alpha1.SeedSecretNameSuffix)
+	defaultSecretName(&usr.Spec.CredentialsSecretName, usr.Name, v1alpha1.CredentialsSecretNameSuffix)
+	defaultIssuerRef(&usr.Spec.Issuer, namespaceOf(ctx, usr.Namespace))
+
+	return nil
+}
+
+// defaultSecretName sets secretName to the resource name with suffix if it is not already set. Resources created with
+// generateName may not have a name yet, in which case the Secret name is left empty and rejected by validation.
+func defaultSecretName(secretName *string, name, suffix string) {
+	if *secretName != "" || name == "" {
+		return
+	}
+
+	*secretName = name + suffix
+}
+
+// defaultIssuerRef sets the namespace of the issuer to that of the referencing resource, which is how the controllers
+// resolve a reference without a namespace, and sets the API version to the current version of the accounts.nats.io
+// group.
+func defaultIssuerRef(issuer *v1alpha1.IssuerReference, namespace string) {
+	if issuer.Ref.Namespace == "" {
+		issuer.Ref.Namespace = namespace
+	}
+
+	if issuer.Ref.APIVersion == "" {
+		issuer.Ref.APIVersion = v1alpha1.GroupVersion.String()
+	}
+}
+
+// namespaceOf returns namespace, or the namespace of the admission request if the object does not have one set.
+func namespaceOf(ctx context.Context, namespace string) string {
+	if namespace != "" {
+		return namespace
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return ""
+	}
+
+	return req.Namespace
+}
@@ -0,0 +1,160 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// requestContext returns a context holding an admission request for namespace.
+func requestContext(namespace string) context.Context {
+	return admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{Namespace: namespace},
+	})
+}
+
+func TestAccountDefaulter(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		meta metav1.ObjectMeta
+		spec v1alpha1.AccountSpec
+		want v1alpha1.AccountSpec
+	}{
+		{
+			name: "defaults",
+			ctx:  context.Background(),
+			meta: testObjectMeta("acc"),
+			spec: v1alpha1.AccountSpec{
+				Issuer: v1alpha1.IssuerReference{Ref: v1alpha1.TypedObjectReference{Kind: "Operator", Name: "op"}},
+			},
+			want: v1alpha1.AccountSpec{
+				Issuer:         testIssuerIn("Operator", "op", testNamespace),
+				JWTSecretName:  "acc-jwt",
+				SeedSecretName: "acc-seed",
+			},
+		},
+		{
+			name: "set values are kept",
+			ctx:  context.Background(),
+			meta: testObjectMeta("acc"),
+			spec: v1alpha1.AccountSpec{
+				Issuer:         testIssuerIn("SigningKey", "sk", "other"),
+				JWTSecretName:  "jwt",
+				SeedSecretName: "seed",
+			},
+			want: v1alpha1.AccountSpec{
+				Issuer:         testIssuerIn("SigningKey", "sk", "other"),
+				JWTSecretName:  "jwt",
+				SeedSecretName: "seed",
+			},
+		},
+		{
+			name: "namespace from the request",
+			ctx:  requestContext("request"),
+			meta: metav1.ObjectMeta{Name: "acc"},
+			spec: v1alpha1.AccountSpec{
+				Issuer: v1alpha1.IssuerReference{Ref: v1alpha1.TypedObjectReference{Kind: "Operator", Name: "op"}},
+			},
+			want: v1alpha1.AccountSpec{
+				Issuer:         testIssuerIn("Operator", "op", "request"),
+				JWTSecretName:  "acc-jwt",
+				SeedSecretName: "acc-seed",
+			},
+		},
+		{
+			name: "generated name",
+			ctx:  context.Background(),
+			meta: metav1.ObjectMeta{GenerateName: "acc-", Namespace: testNamespace},
+			spec: v1alpha1.AccountSpec{
+				Issuer: v1alpha1.IssuerReference{Ref: v1alpha1.TypedObjectReference{Kind: "Operator", Name: "op"}},
+			},
+			want: v1alpha1.AccountSpec{
+				Issuer: testIssuerIn("Operator", "op", testNamespace),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acc := &v1alpha1.Account{ObjectMeta: tt.meta, Spec: tt.spec}
+
+			if err := new(AccountDefaulter).Default(tt.ctx, acc); err != nil {
+				t.Fatalf("Default() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, acc.Spec); diff != "" {
+				t.Errorf("Default() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUserDefaulter(t *testing.T) {
+	tests := []struct {
+		name string
+		meta metav1.ObjectMeta
+		spec v1alpha1.UserSpec
+		want v1alpha1.UserSpec
+	}{
+		{
+			name: "defaults",
+			meta: testObjectMeta("usr"),
+			spec: v1alpha1.UserSpec{
+				Issuer: v1alpha1.IssuerReference{Ref: v1alpha1.TypedObjectReference{Kind: "Account", Name: "acc"}},
+			},
+			want: v1alpha1.UserSpec{
+				Issuer:                testIssuerIn("Account", "acc", testNamespace),
+				JWTSecretName:         "usr-jwt",
+				SeedSecretName:        "usr-seed",
+				CredentialsSecretName: "usr-creds",
+			},
+		},
+		{
+			name: "set values are kept",
+			meta: testObjectMeta("usr"),
+			spec: v1alpha1.UserSpec{
+				Issuer:                testIssuerIn("SigningKey", "sk", "other"),
+				JWTSecretName:         "jwt",
+				SeedSecretName:        "seed",
+				CredentialsSecretName: "creds",
+			},
+			want: v1alpha1.UserSpec{
+				Issuer:                testIssuerIn("SigningKey", "sk", "other"),
+				JWTSecretName:         "jwt",
+				SeedSecretName:        "seed",
+				CredentialsSecretName: "creds",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usr := &v1alpha1.User{ObjectMeta: tt.meta, Spec: tt.spec}
+
+			if err := new(UserDefaulter).Default(context.Background(), usr); err != nil {
+				t.Fatalf("Default() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, usr.Spec); diff != "" {
+				t.Errorf("Default() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaulterWrongType(t *testing.T) {
+	if err := new(AccountDefaulter).Default(context.Background(), &v1alpha1.User{}); err == nil {
+		t.Errorf("AccountDefaulter.Default() error = nil for a User")
+	}
+
+	if err := new(UserDefaulter).Default(context.Background(), &v1alpha1.Account{}); err == nil {
+		t.Errorf("UserDefaulter.Default() error = nil for an Account")
+	}
+}
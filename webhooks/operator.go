@@ -0,0 +1,139 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+)
+
+//+kubebuilder:webhook:path=/validate-accounts-nats-io-v1alpha1-operator,mutating=false,failurePolicy=fail,sideEffects=None,groups=accounts.nats.io,resources=operators,verbs=create;update,versions=v1alpha1,name=voperator.kb.io,admissionReviewVersions=v1
+
+// OperatorValidator validates Operators on create and update.
+type OperatorValidator struct {
+	// Client is used to check that the Secret names are not used by another resource.
+	Client client.Reader
+}
+
+var _ admission.CustomValidator = &OperatorValidator{}
+
+func (v *OperatorValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	operator, ok := obj.(*v1alpha1.Operator)
+	if !ok {
+		return fmt.Errorf("expected an Operator but got %T", obj)
+	}
+
+	return v.validate(ctx, operator, nil)
+}
+
+func (v *OperatorValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	operator, ok := newObj.(*v1alpha1.Operator)
+	if !ok {
+		return fmt.Errorf("expected an Operator but got %T", newObj)
+	}
+
+	old, ok := oldObj.(*v1alpha1.Operator)
+	if !ok {
+		return fmt.Errorf("expected an Operator but got %T", oldObj)
+	}
+
+	return v.validate(ctx, operator, old)
+}
+
+func (v *OperatorValidator) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+// validate checks operator, old is nil when operator is being created.
+func (v *OperatorValidator) validate(ctx context.Context, operator, old *v1alpha1.Operator) error {
+	spec := field.NewPath("spec")
+
+	var errs field.ErrorList
+
+	if old != nil {
+		errs = append(errs, validateImmutable(operator.Spec.JWTSecretName, old.Spec.JWTSecretName, spec.Child("jwtSecretName"))...)
+		errs = append(errs, validateImmutable(operator.Spec.SeedSecretName, old.Spec.SeedSecretName, spec.Child("seedSecretName"))...)
+	}
+
+	if !shouldValidateSpec(operator, old == nil || !equality.Semantic.DeepEqual(operator.Spec, old.Spec)) {
+		return invalid("Operator", operator.Name, errs)
+	}
+
+	errs = append(errs, validateLabelSelector(operator.Spec.AccountsNamespaceSelector, spec.Child("accountsNamespaceSelector"))...)
+	errs = append(errs, validateLabelSelector(operator.Spec.AccountsSelector, spec.Child("accountsSelector"))...)
+	errs = append(errs, validateLabelSelector(operator.Spec.SigningKeysSelector, spec.Child("signingKeysSelector"))...)
+	errs = append(errs, validateTLSConfig(operator.Spec.TLSConfig, spec.Child("tlsConfig"))...)
+
+	names := make(map[string]struct{}, len(operator.Spec.PushTargets))
+
+	for i, target := range operator.Spec.PushTargets {
+		path := spec.Child("pushTargets").Index(i)
+
+		if _, ok := names[target.Name]; ok {
+			errs = append(errs, field.Duplicate(path.Child("name"), target.Name))
+		}
+
+		names[target.Name] = struct{}{}
+
+		errs = append(errs, validateTLSConfig(target.TLSConfig, path.Child("tlsConfig"))...)
+	}
+
+	specErrs, err := specError(nsc.ValidateOperator(operator), spec)
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, specErrs...)
+
+	secretErrs, err := validateSecretNames(ctx, v.Client, secretOwner{kind: "Operator", name: operator.Name}, operator.Namespace, operatorSecretNames(operator, spec), operatorSecretNames(old, spec))
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, secretErrs...)
+
+	return invalid("Operator", operator.Name, errs)
+}
+
+// validateTLSConfig checks that a client certificate is configured either with ClientCertSecretRef, or with both of
+// CertFile and KeyFile.
+func validateTLSConfig(config *v1alpha1.TLSConfig, path *field.Path) field.ErrorList {
+	if config == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+
+	if config.ClientCertSecretRef != nil && (config.CertFile != nil || config.KeyFile != nil) {
+		errs = append(errs, field.Forbidden(path.Child("clientCertSecretRef"), "must not be set with certFile or keyFile"))
+	}
+
+	if config.CertFile != nil && config.KeyFile == nil {
+		errs = append(errs, field.Required(path.Child("keyFile"), "must be set with certFile"))
+	}
+
+	if config.KeyFile != nil && config.CertFile == nil {
+		errs = append(errs, field.Required(path.Child("certFile"), "must be set with keyFile"))
+	}
+
+	return errs
+}
+
+// operatorSecretNames returns the Secret names claimed by operator, or nil if operator is nil.
+func operatorSecretNames(operator *v1alpha1.Operator, spec *field.Path) []secretName {
+	if operator == nil {
+		return nil
+	}
+
+	return []secretName{
+		{name: operator.Spec.JWTSecretName, path: spec.Child("jwtSecretName")},
+		{name: operator.Spec.SeedSecretName, path: spec.Child("seedSecretName")},
+	}
+}
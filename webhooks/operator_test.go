@@ -0,0 +1,126 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// newTestOperator returns a valid Operator with mutate applied.
+func newTestOperator(name string, mutate ...func(operator *v1alpha1.Operator)) *v1alpha1.Operator {
+	operator := &v1alpha1.Operator{
+		ObjectMeta: testObjectMeta(name),
+		Spec: v1alpha1.OperatorSpec{
+			JWTSecretName:  name + "-jwt",
+			SeedSecretName: name + "-seed",
+		},
+	}
+
+	for _, m := range mutate {
+		m(operator)
+	}
+
+	return operator
+}
+
+func secretKey(name, key string) *v1.SecretKeySelector {
+	return &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: name}, Key: key}
+}
+
+// withInvalidOperatorSpec sets a spec which is rejected by the jwt validation and an invalid label selector.
+func withInvalidOperatorSpec(operator *v1alpha1.Operator) {
+	operator.Spec.AccountsSelector = invalidSelector
+	operator.Spec.OperatorServiceURLs = []string{"http://nats:4222"}
+}
+
+func TestOperatorValidator(t *testing.T) {
+	tests := []struct {
+		name       string
+		operator   *v1alpha1.Operator
+		old        *v1alpha1.Operator
+		existing   []client.Object
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			operator: newTestOperator("op", func(operator *v1alpha1.Operator) {
+				operator.Spec.PushTargets = []v1alpha1.PushTarget{
+					{Name: "a", URL: "nats://a:4222"},
+					{Name: "b", URL: "nats://b:4222", TLSConfig: &v1alpha1.TLSConfig{CertFile: secretKey("tls", "tls.crt"), KeyFile: secretKey("tls", "tls.key")}},
+				}
+			}),
+		},
+		{
+			name:       "invalid spec",
+			operator:   newTestOperator("op", withInvalidOperatorSpec),
+			wantFields: []string{"spec", "spec.accountsSelector"},
+		},
+		{
+			name: "invalid push targets",
+			operator: newTestOperator("op", func(operator *v1alpha1.Operator) {
+				operator.Spec.TLSConfig = &v1alpha1.TLSConfig{KeyFile: secretKey("tls", "tls.key")}
+				operator.Spec.PushTargets = []v1alpha1.PushTarget{
+					{Name: "a", URL: "nats://a:4222", TLSConfig: &v1alpha1.TLSConfig{CertFile: secretKey("tls", "tls.crt")}},
+					{Name: "a", URL: "nats://b:4222"},
+				}
+			}),
+			wantFields: []string{"spec.pushTargets[0].tlsConfig.keyFile", "spec.pushTargets[1].name", "spec.tlsConfig.certFile"},
+		},
+		{
+			name:       "secret used by another resource",
+			operator:   newTestOperator("op", func(operator *v1alpha1.Operator) { operator.Spec.SeedSecretName = "acc-seed" }),
+			existing:   []client.Object{newTestAccount("acc")},
+			wantFields: []string{"spec.seedSecretName"},
+		},
+		{
+			name: "secret names are immutable",
+			operator: newTestOperator("op", func(operator *v1alpha1.Operator) {
+				operator.Spec.JWTSecretName = "renamed-jwt"
+				operator.Spec.SeedSecretName = "renamed-seed"
+			}),
+			old:        newTestOperator("op"),
+			wantFields: []string{"spec.jwtSecretName", "spec.seedSecretName"},
+		},
+		{
+			name: "spec unchanged",
+			operator: newTestOperator("op", withInvalidOperatorSpec, func(operator *v1alpha1.Operator) {
+				operator.Labels = map[string]string{"app": "nats"}
+			}),
+			old: newTestOperator("op", withInvalidOperatorSpec),
+		},
+		{
+			name: "spec changed",
+			operator: newTestOperator("op", withInvalidOperatorSpec, func(operator *v1alpha1.Operator) {
+				operator.Spec.Tags = []string{"tag"}
+			}),
+			old:        newTestOperator("op", withInvalidOperatorSpec),
+			wantFields: []string{"spec", "spec.accountsSelector"},
+		},
+		{
+			name: "deleting",
+			operator: newTestOperator("op", withInvalidOperatorSpec, func(operator *v1alpha1.Operator) {
+				operator.DeletionTimestamp = &deleting
+			}),
+			old: newTestOperator("op"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &OperatorValidator{Client: newFakeClient(tt.existing...)}
+
+			var err error
+			if tt.old == nil {
+				err = v.ValidateCreate(context.Background(), tt.operator)
+			} else {
+				err = v.ValidateUpdate(context.Background(), tt.old, tt.operator)
+			}
+
+			assertFieldErrors(t, err, tt.wantFields)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// secretName is a Secret name claimed by a field of a resource.
+type secretName struct {
+	name string
+	path *field.Path
+}
+
+// secretOwner identifies the resource which claims a Secret name.
+type secretOwner struct {
+	kind string
+	name string
+}
+
+// validateSecretNames checks that every Secret name in want is set, and that those which are not in previous are not
+// already claimed by another Operator, Account, User or SigningKey in the namespace. Only new names are checked for
+// conflicts so that resources which already share a Secret can still be updated.
+func validateSecretNames(ctx context.Context, c client.Reader, self secretOwner, namespace string, want, previous []secretName) (field.ErrorList, error) {
+	var errs field.ErrorList
+
+	existing := make(map[string]bool, len(previous))
+	for _, sn := range previous {
+		existing[sn.name] = true
+	}
+
+	var check []secretName
+
+	for _, sn := range want {
+		if sn.name == "" {
+			errs = append(errs, field.Required(sn.path, ""))
+
+			continue
+		}
+
+		if !existing[sn.name] {
+			check = append(check, sn)
+		}
+	}
+
+	if len(check) == 0 {
+		return errs, nil
+	}
+
+	claimed, err := claimedSecretNames(ctx, c, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sn := range check {
+		for _, owner := range claimed[sn.name] {
+			if owner == self {
+				continue
+			}
+
+			errs = append(errs, field.Invalid(sn.path, sn.name, fmt.Sprintf("already used by %s %s/%s", owner.kind, namespace, owner.name)))
+
+			break
+		}
+	}
+
+	return errs, nil
+}
+
+// claimedSecretNames returns the resources in namespace which claim each Secret name.
+func claimedSecretNames(ctx context.Context, c client.Reader, namespace string) (map[string][]secretOwner, error) {
+	claimed := make(map[string][]secretOwner)
+
+	claim := func(owner secretOwner, names ...string) {
+		for _, name := range names {
+			if name != "" {
+				claimed[name] = append(claimed[name], owner)
+			}
+		}
+	}
+
+	operators := new(v1alpha1.OperatorList)
+	if err := c.List(ctx, operators, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list operators: %w", err)
+	}
+
+	for _, operator := range operators.Items {
+		claim(secretOwner{kind: "Operator", name: operator.Name}, operator.Spec.JWTSecretName, operator.Spec.SeedSecretName)
+	}
+
+	accounts := new(v1alpha1.AccountList)
+	if err := c.List(ctx, accounts, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	for i := range accounts.Items {
+		acc := &accounts.Items[i]
+		claim(secretOwner{kind: "Account", name: acc.Name}, acc.Spec.JWTSecretName, acc.Spec.SeedSecretName, xKeySecretName(acc))
+	}
+
+	users := new(v1alpha1.UserList)
+	if err := c.List(ctx, users, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	for _, usr := range users.Items {
+		claim(secretOwner{kind: "User", name: usr.Name}, usr.Spec.JWTSecretName, usr.Spec.SeedSecretName, usr.Spec.CredentialsSecretName)
+	}
+
+	signingKeys := new(v1alpha1.SigningKeyList)
+	if err := c.List(ctx, signingKeys, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+
+	for _, sk := range signingKeys.Items {
+		owner := secretOwner{kind: "SigningKey", name: sk.Name}
+
+		// rotated keypairs are stored in new Secrets, which remain in use until they are retired
+		claim(owner, sk.Spec.SeedSecretName)
+
+		if sk.Status.KeyPair != nil && sk.Status.KeyPair.SeedSecretName != sk.Spec.SeedSecretName {
+			claim(owner, sk.Status.KeyPair.SeedSecretName)
+		}
+
+		for _, retiring := range sk.Status.RetiringKeyPairs {
+			claim(owner, retiring.SeedSecretName)
+		}
+	}
+
+	return claimed, nil
+}
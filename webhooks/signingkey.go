@@ -0,0 +1,136 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+)
+
+//+kubebuilder:webhook:path=/validate-accounts-nats-io-v1alpha1-signingkey,mutating=false,failurePolicy=fail,sideEffects=None,groups=accounts.nats.io,resources=signingkeys,verbs=create;update,versions=v1alpha1,name=vsigningkey.kb.io,admissionReviewVersions=v1
+
+// SigningKeyValidator validates SigningKeys on create and update.
+type SigningKeyValidator struct {
+	// Client is used to check that the Secret names are not used by another resource.
+	Client client.Reader
+}
+
+var _ admission.CustomValidator = &SigningKeyValidator{}
+
+func (v *SigningKeyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	sk, ok := obj.(*v1alpha1.SigningKey)
+	if !ok {
+		return fmt.Errorf("expected a SigningKey but got %T", obj)
+	}
+
+	return v.validate(ctx, sk, nil)
+}
+
+func (v *SigningKeyValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	sk, ok := newObj.(*v1alpha1.SigningKey)
+	if !ok {
+		return fmt.Errorf("expected a SigningKey but got %T", newObj)
+	}
+
+	old, ok := oldObj.(*v1alpha1.SigningKey)
+	if !ok {
+		return fmt.Errorf("expected a SigningKey but got %T", oldObj)
+	}
+
+	return v.validate(ctx, sk, old)
+}
+
+func (v *SigningKeyValidator) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+// validate checks sk, old is nil when sk is being created.
+func (v *SigningKeyValidator) validate(ctx context.Context, sk, old *v1alpha1.SigningKey) error {
+	spec := field.NewPath("spec")
+
+	var errs field.ErrorList
+
+	if old != nil {
+		errs = append(errs, validateImmutable(sk.Spec.SeedSecretName, old.Spec.SeedSecretName, spec.Child("seedSecretName"))...)
+	}
+
+	if !shouldValidateSpec(sk, old == nil || !equality.Semantic.DeepEqual(sk.Spec, old.Spec)) {
+		return invalid("SigningKey", sk.Name, errs)
+	}
+
+	kinds := []string{v1alpha1.SigningKeyTypeOperator, v1alpha1.SigningKeyTypeAccount}
+
+	if !contains(kinds, string(sk.Spec.Type)) {
+		errs = append(errs, field.NotSupported(spec.Child("type"), sk.Spec.Type, kinds))
+	}
+
+	ownerRef := sk.Spec.OwnerRef
+	ownerPath := spec.Child("ownerRef")
+
+	if ownerRef.Name == "" {
+		errs = append(errs, field.Required(ownerPath.Child("name"), ""))
+	}
+
+	// the API version may be omitted in v1beta1, in which case the owner is in the accounts.nats.io group
+	if ownerRef.APIVersion != "" {
+		gv, err := schema.ParseGroupVersion(ownerRef.APIVersion)
+		if err != nil {
+			errs = append(errs, field.Invalid(ownerPath.Child("apiVersion"), ownerRef.APIVersion, err.Error()))
+		} else if gv.Group != v1alpha1.GroupVersion.Group {
+			errs = append(errs, field.Invalid(ownerPath.Child("apiVersion"), ownerRef.APIVersion, "must be in the "+v1alpha1.GroupVersion.Group+" group"))
+		}
+	}
+
+	switch {
+	case !contains(kinds, ownerRef.Kind):
+		errs = append(errs, field.NotSupported(ownerPath.Child("kind"), ownerRef.Kind, kinds))
+	case ownerRef.Kind != string(sk.Spec.Type):
+		// the keypair is generated for the owner's kind, so a mismatched type would never be usable
+		errs = append(errs, field.Invalid(ownerPath.Child("kind"), ownerRef.Kind, "must match spec.type"))
+	}
+
+	if sk.Spec.Scope != nil {
+		if ownerRef.Kind != v1alpha1.SigningKeyTypeAccount {
+			errs = append(errs, field.Forbidden(spec.Child("scope"), "scoped signing keys are only supported for Accounts"))
+		}
+
+		specErrs, err := specError(nsc.ValidateSigningKeyScope(sk.Spec.Scope), spec.Child("scope", "template"))
+		if err != nil {
+			return err
+		}
+
+		errs = append(errs, specErrs...)
+	}
+
+	if sk.Spec.Rotation != nil && sk.Spec.Rotation.Interval.Duration <= 0 {
+		errs = append(errs, field.Invalid(spec.Child("rotation", "interval"), sk.Spec.Rotation.Interval.Duration.String(), "must be greater than zero"))
+	}
+
+	secretErrs, err := validateSecretNames(ctx, v.Client, secretOwner{kind: "SigningKey", name: sk.Name}, sk.Namespace, signingKeySecretNames(sk, spec), signingKeySecretNames(old, spec))
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, secretErrs...)
+
+	return invalid("SigningKey", sk.Name, errs)
+}
+
+// signingKeySecretNames returns the Secret names claimed by sk, or nil if sk is nil.
+func signingKeySecretNames(sk *v1alpha1.SigningKey, spec *field.Path) []secretName {
+	if sk == nil {
+		return nil
+	}
+
+	return []secretName{
+		{name: sk.Spec.SeedSecretName, path: spec.Child("seedSecretName")},
+	}
+}
@@ -0,0 +1,155 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// newTestSigningKey returns a valid SigningKey owned by the Account "acc" with mutate applied.
+func newTestSigningKey(name string, mutate ...func(sk *v1alpha1.SigningKey)) *v1alpha1.SigningKey {
+	sk := &v1alpha1.SigningKey{
+		ObjectMeta: testObjectMeta(name),
+		Spec: v1alpha1.SigningKeySpec{
+			Type:           v1alpha1.SigningKeyTypeAccount,
+			SeedSecretName: name + "-seed",
+			OwnerRef: v1alpha1.SigningKeyOwnerReference{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       v1alpha1.SigningKeyTypeAccount,
+				Name:       "acc",
+			},
+		},
+	}
+
+	for _, m := range mutate {
+		m(sk)
+	}
+
+	return sk
+}
+
+// withInvalidScope sets a scope which is rejected by the jwt validation.
+func withInvalidScope(sk *v1alpha1.SigningKey) {
+	sk.Spec.Scope = &v1alpha1.SigningKeyScope{
+		Role: "role",
+		Template: v1alpha1.UserPermissionLimits{
+			Limits: v1alpha1.UserLimits{Src: []string{"not-a-cidr"}},
+		},
+	}
+}
+
+func TestSigningKeyValidator(t *testing.T) {
+	tests := []struct {
+		name       string
+		sk         *v1alpha1.SigningKey
+		old        *v1alpha1.SigningKey
+		existing   []client.Object
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			sk: newTestSigningKey("sk", func(sk *v1alpha1.SigningKey) {
+				sk.Spec.Scope = &v1alpha1.SigningKeyScope{Role: "role"}
+				sk.Spec.Rotation = &v1alpha1.SigningKeyRotationPolicy{Interval: metav1.Duration{Duration: time.Hour}}
+			}),
+		},
+		{
+			name: "owner without api version",
+			sk: newTestSigningKey("sk", func(sk *v1alpha1.SigningKey) {
+				sk.Spec.OwnerRef.APIVersion = ""
+			}),
+		},
+		{
+			name: "invalid owner",
+			sk: newTestSigningKey("sk", func(sk *v1alpha1.SigningKey) {
+				sk.Spec.Type = "User"
+				sk.Spec.OwnerRef = v1alpha1.SigningKeyOwnerReference{APIVersion: "v1", Kind: "User"}
+			}),
+			wantFields: []string{"spec.ownerRef.apiVersion", "spec.ownerRef.kind", "spec.ownerRef.name", "spec.type"},
+		},
+		{
+			name: "type does not match owner",
+			sk: newTestSigningKey("sk", func(sk *v1alpha1.SigningKey) {
+				sk.Spec.Type = v1alpha1.SigningKeyTypeOperator
+			}),
+			wantFields: []string{"spec.ownerRef.kind"},
+		},
+		{
+			name:       "invalid scope",
+			sk:         newTestSigningKey("sk", withInvalidScope),
+			wantFields: []string{"spec.scope.template"},
+		},
+		{
+			name: "scoped operator signing key",
+			sk: newTestSigningKey("sk", func(sk *v1alpha1.SigningKey) {
+				sk.Spec.Type = v1alpha1.SigningKeyTypeOperator
+				sk.Spec.OwnerRef.Kind = v1alpha1.SigningKeyTypeOperator
+				sk.Spec.Scope = &v1alpha1.SigningKeyScope{Role: "role"}
+			}),
+			wantFields: []string{"spec.scope"},
+		},
+		{
+			name: "invalid rotation interval",
+			sk: newTestSigningKey("sk", func(sk *v1alpha1.SigningKey) {
+				sk.Spec.Rotation = &v1alpha1.SigningKeyRotationPolicy{}
+			}),
+			wantFields: []string{"spec.rotation.interval"},
+		},
+		{
+			name: "secret used by a rotated keypair",
+			sk:   newTestSigningKey("sk", func(sk *v1alpha1.SigningKey) { sk.Spec.SeedSecretName = "other-seed-1" }),
+			existing: []client.Object{newTestSigningKey("other", func(sk *v1alpha1.SigningKey) {
+				sk.Status.KeyPair = &v1alpha1.KeyPair{PublicKey: "AOTHER", SeedSecretName: "other-seed-1"}
+			})},
+			wantFields: []string{"spec.seedSecretName"},
+		},
+		{
+			name:       "secret name is immutable",
+			sk:         newTestSigningKey("sk", func(sk *v1alpha1.SigningKey) { sk.Spec.SeedSecretName = "renamed-seed" }),
+			old:        newTestSigningKey("sk"),
+			wantFields: []string{"spec.seedSecretName"},
+		},
+		{
+			name: "spec unchanged",
+			sk: newTestSigningKey("sk", withInvalidScope, func(sk *v1alpha1.SigningKey) {
+				sk.Finalizers = []string{"accounts.nats.io/finalizer"}
+			}),
+			old: newTestSigningKey("sk", withInvalidScope),
+		},
+		{
+			name: "spec changed",
+			sk: newTestSigningKey("sk", withInvalidScope, func(sk *v1alpha1.SigningKey) {
+				sk.Spec.Scope.Role = "other"
+			}),
+			old:        newTestSigningKey("sk", withInvalidScope),
+			wantFields: []string{"spec.scope.template"},
+		},
+		{
+			name: "deleting",
+			sk: newTestSigningKey("sk", withInvalidScope, func(sk *v1alpha1.SigningKey) {
+				sk.DeletionTimestamp = &deleting
+			}),
+			old: newTestSigningKey("sk"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &SigningKeyValidator{Client: newFakeClient(tt.existing...)}
+
+			var err error
+			if tt.old == nil {
+				err = v.ValidateCreate(context.Background(), tt.sk)
+			} else {
+				err = v.ValidateUpdate(context.Background(), tt.old, tt.sk)
+			}
+
+			assertFieldErrors(t, err, tt.wantFields)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+)
+
+//+kubebuilder:webhook:path=/validate-accounts-nats-io-v1alpha1-user,mutating=false,failurePolicy=fail,sideEffects=None,groups=accounts.nats.io,resources=users,verbs=create;update,versions=v1alpha1,name=vuser.kb.io,admissionReviewVersions=v1
+
+// UserValidator validates Users on create and update.
+type UserValidator struct {
+	// Client is used to check that the Secret names are not used by another resource.
+	Client client.Reader
+}
+
+var _ admission.CustomValidator = &UserValidator{}
+
+func (v *UserValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	usr, ok := obj.(*v1alpha1.User)
+	if !ok {
+		return fmt.Errorf("expected a User but got %T", obj)
+	}
+
+	return v.validate(ctx, usr, nil)
+}
+
+func (v *UserValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	usr, ok := newObj.(*v1alpha1.User)
+	if !ok {
+		return fmt.Errorf("expected a User but got %T", newObj)
+	}
+
+	old, ok := oldObj.(*v1alpha1.User)
+	if !ok {
+		return fmt.Errorf("expected a User but got %T", oldObj)
+	}
+
+	return v.validate(ctx, usr, old)
+}
+
+func (v *UserValidator) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+// validate checks usr, old is nil when usr is being created.
+func (v *UserValidator) validate(ctx context.Context, usr, old *v1alpha1.User) error {
+	spec := field.NewPath("spec")
+
+	var errs field.ErrorList
+
+	if old != nil {
+		errs = append(errs, validateImmutable(usr.Spec.JWTSecretName, old.Spec.JWTSecretName, spec.Child("jwtSecretName"))...)
+		errs = append(errs, validateImmutable(usr.Spec.SeedSecretName, old.Spec.SeedSecretName, spec.Child("seedSecretName"))...)
+		errs = append(errs, validateImmutable(usr.Spec.CredentialsSecretName, old.Spec.CredentialsSecretName, spec.Child("credentialsSecretName"))...)
+	}
+
+	if !shouldValidateSpec(usr, old == nil || !equality.Semantic.DeepEqual(usr.Spec, old.Spec)) {
+		return invalid("User", usr.Name, errs)
+	}
+
+	errs = append(errs, validateIssuerRef(usr.Spec.Issuer, spec.Child("issuer", "ref"), "Account", "SigningKey")...)
+
+	issuerErrs, err := v.validateAccountIssuer(ctx, usr, spec.Child("issuer", "ref", "kind"))
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, issuerErrs...)
+
+	specErrs, err := specError(nsc.ValidateUser(usr), spec)
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, specErrs...)
+
+	secretErrs, err := validateSecretNames(ctx, v.Client, secretOwner{kind: "User", name: usr.Name}, usr.Namespace, userSecretNames(usr, spec), userSecretNames(old, spec))
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, secretErrs...)
+
+	return invalid("User", usr.Name, errs)
+}
+
+// validateAccountIssuer rejects a User issued directly by an Account whose Operator requires strict signing key usage,
+// since the servers would reject the User JWT. An Account or Operator which cannot be found, or an Account which has not
+// resolved its Operator yet, is left to the controller.
+func (v *UserValidator) validateAccountIssuer(ctx context.Context, usr *v1alpha1.User, path *field.Path) (field.ErrorList, error) {
+	ref := usr.Spec.Issuer.Ref
+	if ref.Kind != "Account" {
+		return nil, nil
+	}
+
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = usr.Namespace
+	}
+
+	acc := new(v1alpha1.Account)
+
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, acc); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	operatorRef := acc.Status.OperatorRef
+	if operatorRef == nil {
+		return nil, nil
+	}
+
+	operator := new(v1alpha1.Operator)
+
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: operatorRef.Namespace, Name: operatorRef.Name}, operator); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	if !operator.Spec.StrictSigningKeyUsage {
+		return nil, nil
+	}
+
+	return field.ErrorList{
+		field.Forbidden(path, fmt.Sprintf("operator %s/%s requires strict signing key usage, users must be issued by a SigningKey of account %s/%s", operatorRef.Namespace, operatorRef.Name, namespace, ref.Name)),
+	}, nil
+}
+
+// userSecretNames returns the Secret names claimed by usr, or nil if usr is nil.
+func userSecretNames(usr *v1alpha1.User, spec *field.Path) []secretName {
+	if usr == nil {
+		return nil
+	}
+
+	return []secretName{
+		{name: usr.Spec.JWTSecretName, path: spec.Child("jwtSecretName")},
+		{name: usr.Spec.SeedSecretName, path: spec.Child("seedSecretName")},
+		{name: usr.Spec.CredentialsSecretName, path: spec.Child("credentialsSecretName")},
+	}
+}
@@ -0,0 +1,131 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// newTestUser returns a valid User issued by the Account "acc" with mutate applied.
+func newTestUser(name string, mutate ...func(usr *v1alpha1.User)) *v1alpha1.User {
+	usr := &v1alpha1.User{
+		ObjectMeta: testObjectMeta(name),
+		Spec: v1alpha1.UserSpec{
+			Issuer:                testIssuer("Account", "acc"),
+			JWTSecretName:         name + "-jwt",
+			SeedSecretName:        name + "-seed",
+			CredentialsSecretName: name + "-creds",
+		},
+	}
+
+	for _, m := range mutate {
+		m(usr)
+	}
+
+	return usr
+}
+
+// newTestResolvedAccount returns the Account "acc" which has resolved the Operator "op".
+func newTestResolvedAccount() *v1alpha1.Account {
+	acc := newTestAccount("acc")
+	acc.Status.OperatorRef = &v1alpha1.InferredObjectReference{Namespace: testNamespace, Name: "op"}
+
+	return acc
+}
+
+// withInvalidUserSpec sets a spec which is rejected by the jwt validation.
+func withInvalidUserSpec(usr *v1alpha1.User) {
+	usr.Spec.Limits.Src = []string{"not-a-cidr"}
+}
+
+func TestUserValidator(t *testing.T) {
+	tests := []struct {
+		name       string
+		usr        *v1alpha1.User
+		old        *v1alpha1.User
+		existing   []client.Object
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			usr:  newTestUser("usr"),
+		},
+		{
+			name:       "issued by an operator",
+			usr:        newTestUser("usr", func(usr *v1alpha1.User) { usr.Spec.Issuer = testIssuer("Operator", "op") }),
+			wantFields: []string{"spec.issuer.ref.kind"},
+		},
+		{
+			name:       "issued by an account whose operator requires strict signing key usage",
+			usr:        newTestUser("usr"),
+			existing:   []client.Object{newTestResolvedAccount(), newTestStrictOperator()},
+			wantFields: []string{"spec.issuer.ref.kind"},
+		},
+		{
+			name:     "issued by a signing key of an account whose operator requires strict signing key usage",
+			usr:      newTestUser("usr", func(usr *v1alpha1.User) { usr.Spec.Issuer = testIssuer("SigningKey", "acc-sk") }),
+			existing: []client.Object{newTestResolvedAccount(), newTestStrictOperator()},
+		},
+		{
+			name:       "invalid spec",
+			usr:        newTestUser("usr", withInvalidUserSpec),
+			wantFields: []string{"spec"},
+		},
+		{
+			name:       "secret used by another user",
+			usr:        newTestUser("usr", func(usr *v1alpha1.User) { usr.Spec.CredentialsSecretName = "other-creds" }),
+			existing:   []client.Object{newTestUser("other")},
+			wantFields: []string{"spec.credentialsSecretName"},
+		},
+		{
+			name: "secret names are immutable",
+			usr: newTestUser("usr", func(usr *v1alpha1.User) {
+				usr.Spec.JWTSecretName = "renamed-jwt"
+				usr.Spec.SeedSecretName = "renamed-seed"
+				usr.Spec.CredentialsSecretName = "renamed-creds"
+			}),
+			old:        newTestUser("usr"),
+			wantFields: []string{"spec.credentialsSecretName", "spec.jwtSecretName", "spec.seedSecretName"},
+		},
+		{
+			name: "spec unchanged",
+			usr: newTestUser("usr", withInvalidUserSpec, func(usr *v1alpha1.User) {
+				usr.Finalizers = []string{"accounts.nats.io/finalizer"}
+			}),
+			old: newTestUser("usr", withInvalidUserSpec),
+		},
+		{
+			name: "spec changed",
+			usr: newTestUser("usr", withInvalidUserSpec, func(usr *v1alpha1.User) {
+				usr.Spec.Limits.Src = append(usr.Spec.Limits.Src, "10.0.0.0/8")
+			}),
+			old:        newTestUser("usr", withInvalidUserSpec),
+			wantFields: []string{"spec"},
+		},
+		{
+			name: "deleting",
+			usr: newTestUser("usr", withInvalidUserSpec, func(usr *v1alpha1.User) {
+				usr.DeletionTimestamp = &deleting
+			}),
+			old: newTestUser("usr"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &UserValidator{Client: newFakeClient(tt.existing...)}
+
+			var err error
+			if tt.old == nil {
+				err = v.ValidateCreate(context.Background(), tt.usr)
+			} else {
+				err = v.ValidateUpdate(context.Background(), tt.old, tt.usr)
+			}
+
+			assertFieldErrors(t, err, tt.wantFields)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package webhooks implements the admission webhooks for the accounts.nats.io resources. The validating webhooks run
+// the same nsc conversion and jwt claim validation used by the controllers, so that specs which could never be turned
+// into a JWT are rejected when they are applied rather than surfacing as an InvalidSpec condition later.
+package webhooks
+
+import (
+	"errors"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+)
+
+// SetupWithManager registers the webhooks for all resources with the manager's webhook server. The conversion webhook is
+// registered along with them since v1alpha1 is the conversion hub, and the admission webhooks only handle v1alpha1 as
+// the API server converts requests for other versions before calling them.
+func SetupWithManager(mgr ctrl.Manager) error {
+	c := mgr.GetClient()
+
+	err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Operator{}).
+		WithValidator(&OperatorValidator{Client: c}).
+		Complete()
+	if err != nil {
+		return err
+	}
+
+	err = ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Account{}).
+		WithDefaulter(&AccountDefaulter{}).
+		WithValidator(&AccountValidator{Client: c}).
+		Complete()
+	if err != nil {
+		return err
+	}
+
+	err = ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.User{}).
+		WithDefaulter(&UserDefaulter{}).
+		WithValidator(&UserValidator{Client: c}).
+		Complete()
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.SigningKey{}).
+		WithValidator(&SigningKeyValidator{Client: c}).
+		Complete()
+}
+
+// invalid returns an Invalid API error for the named resource, or nil if there are no errors.
+func invalid(kind, name string, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind(kind).GroupKind(), name, errs)
+}
+
+// validateLabelSelector checks that selector can be converted with metav1.LabelSelectorAsSelector, as the controllers
+// do when listing the resources it selects.
+func validateLabelSelector(selector *metav1.LabelSelector, path *field.Path) field.ErrorList {
+	if selector == nil {
+		return nil
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		return field.ErrorList{field.Invalid(path, field.OmitValueType{}, err.Error())}
+	}
+
+	return nil
+}
+
+// validateIssuerRef checks that the issuer references one of the kinds in the accounts.nats.io group which are able
+// to issue the resource.
+func validateIssuerRef(issuer v1alpha1.IssuerReference, path *field.Path, kinds ...string) field.ErrorList {
+	var errs field.ErrorList
+
+	ref := issuer.Ref
+
+	if ref.Name == "" {
+		errs = append(errs, field.Required(path.Child("name"), ""))
+	}
+
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		errs = append(errs, field.Invalid(path.Child("apiVersion"), ref.APIVersion, err.Error()))
+	} else if gv.Group != v1alpha1.GroupVersion.Group {
+		errs = append(errs, field.Invalid(path.Child("apiVersion"), ref.APIVersion, "must be in the "+v1alpha1.GroupVersion.Group+" group"))
+	}
+
+	if !contains(kinds, ref.Kind) {
+		errs = append(errs, field.NotSupported(path.Child("kind"), ref.Kind, kinds))
+	}
+
+	return errs
+}
+
+// shouldValidateSpec returns whether the spec of obj must be validated. A resource which is being deleted, or updated
+// without changing its spec such as when a finalizer is removed, must not be rejected because its spec would no longer
+// be accepted.
+func shouldValidateSpec(obj metav1.Object, specChanged bool) bool {
+	return obj.GetDeletionTimestamp() == nil && specChanged
+}
+
+// validateImmutable returns an error if a field which cannot be changed after creation has been modified.
+func validateImmutable(newValue, oldValue string, path *field.Path) field.ErrorList {
+	if newValue == oldValue {
+		return nil
+	}
+
+	return field.ErrorList{field.Forbidden(path, "field is immutable")}
+}
+
+// specError converts an error from the nsc validation functions into a field error on the spec. Errors which are not
+// caused by an invalid spec are returned separately since they should not be reported as a validation failure.
+func specError(err error, path *field.Path) (field.ErrorList, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	if !errors.Is(err, nsc.ErrInvalidSpec) {
+		return nil, err
+	}
+
+	msg := strings.TrimPrefix(err.Error(), nsc.ErrInvalidSpec.Error()+": ")
+
+	return field.ErrorList{field.Invalid(path, field.OmitValueType{}, msg)}, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
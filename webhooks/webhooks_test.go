@@ -0,0 +1,93 @@
+package webhooks
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+const testNamespace = "nats"
+
+var (
+	deleting = metav1.Now()
+
+	invalidSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Unknown"}},
+	}
+)
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func testObjectMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: testNamespace}
+}
+
+func testIssuer(kind, name string) v1alpha1.IssuerReference {
+	return testIssuerIn(kind, name, "")
+}
+
+func testIssuerIn(kind, name, namespace string) v1alpha1.IssuerReference {
+	return v1alpha1.IssuerReference{
+		Ref: v1alpha1.TypedObjectReference{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       kind,
+			Name:       name,
+			Namespace:  namespace,
+		},
+	}
+}
+
+// newTestStrictOperator returns the Operator "op" which requires strict signing key usage.
+func newTestStrictOperator() *v1alpha1.Operator {
+	return &v1alpha1.Operator{
+		ObjectMeta: testObjectMeta("op"),
+		Spec:       v1alpha1.OperatorSpec{StrictSigningKeyUsage: true},
+	}
+}
+
+// assertFieldErrors checks that err is an Invalid error for wantFields, or nil if wantFields is empty.
+func assertFieldErrors(t *testing.T, err error, wantFields []string) {
+	t.Helper()
+
+	if err == nil {
+		if len(wantFields) > 0 {
+			t.Errorf("error = nil, want errors for %v", wantFields)
+		}
+
+		return
+	}
+
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || !apierrors.IsInvalid(err) {
+		t.Fatalf("error = %v, want an Invalid error", err)
+	}
+
+	var fields []string
+	for _, cause := range status.Status().Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+
+	want := append([]string(nil), wantFields...)
+
+	sort.Strings(want)
+	sort.Strings(fields)
+
+	if diff := cmp.Diff(want, fields); diff != "" {
+		t.Errorf("error fields mismatch (-want +got):\n%s\nerror: %v", diff, err)
+	}
+}